@@ -0,0 +1,366 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/driver"
+)
+
+const arraySuffix = "[]"
+
+var (
+	columnAttributeRegex = regexp.MustCompile(`^(.+) (collate|compression|storage) (\S+)$`)
+	intervalFieldsRegex  = regexp.MustCompile(`^interval ((?:year|month|day|hour|minute|second)(?: to (?:month|hour|minute|second))?)(?:\((\d+)\))?$`)
+	sqlTypeModifierRegex = regexp.MustCompile(`\s*\(([\d,\s]+)\)`)
+	typeModifierRegex    = regexp.MustCompile(`^(\w+)\((\d+)(?:,\s*(\d+))?\)$`)
+	// typeAliases maps the names of SQL types to the internal names of PostgreSQL.
+	typeAliases = map[string]string{
+		"bigint":                      "int8",
+		"bit varying":                 "varbit",
+		"boolean":                     "bool",
+		"char":                        "bpchar",
+		"character":                   "bpchar",
+		"character varying":           "varchar",
+		"decimal":                     "numeric",
+		"double precision":            "float8",
+		"int":                         "int4",
+		"integer":                     "int4",
+		"real":                        "float4",
+		"smallint":                    "int2",
+		"time with time zone":         "timetz",
+		"time without time zone":      "time",
+		"timestamp with time zone":    "timestamptz",
+		"timestamp without time zone": "timestamp",
+	}
+)
+
+// Array returns the column type of an array whose elements are of the given type, it can be used
+// with Blueprint.Column, e.g. table.Column("tags", postgres.Array("string")) creates a varchar[] column.
+func Array(ttype string, dimensions ...int) string {
+	dimension := 1
+	if len(dimensions) > 0 && dimensions[0] > 1 {
+		dimension = dimensions[0]
+	}
+
+	return ttype + strings.Repeat(arraySuffix, dimension)
+}
+
+// Collate returns the column type with a collation, it can be used with Blueprint.Column,
+// e.g. table.Column("email", postgres.Collate("string", "und-x-icu")).
+func Collate(ttype, collation string) string {
+	return ttype + " collate " + collation
+}
+
+// Compression returns the column type with a compression method, lz4 or pglz, it requires PostgreSQL 14 or later.
+func Compression(ttype, method string) string {
+	return ttype + " compression " + method
+}
+
+// Geography returns the column type of a PostGIS geography, e.g. Geography("point") returns geography(point, 4326).
+func Geography(subtype string, srid ...int) string {
+	if len(srid) > 0 {
+		return spatialType("geography", subtype, srid[0])
+	}
+
+	return spatialType("geography", subtype, 4326)
+}
+
+// Geometry returns the column type of a PostGIS geometry, e.g. Geometry("point", 4326) returns geometry(point, 4326).
+func Geometry(subtype string, srid ...int) string {
+	if len(srid) > 0 {
+		return spatialType("geometry", subtype, srid[0])
+	}
+
+	return spatialType("geometry", subtype, 0)
+}
+
+// HalfVector returns the column type of a pgvector half precision vector, e.g. HalfVector(1536) returns halfVector(1536).
+func HalfVector(dimensions int) string {
+	return vectorType("halfVector", dimensions)
+}
+
+// SparseVector returns the column type of a pgvector sparse vector, e.g. SparseVector(30000) returns sparseVector(30000).
+func SparseVector(dimensions int) string {
+	return vectorType("sparseVector", dimensions)
+}
+
+// Vector returns the column type of a pgvector vector, e.g. Vector(1536) returns vector(1536).
+func Vector(dimensions int) string {
+	return vectorType("vector", dimensions)
+}
+
+// Using returns the column type with the expression that converts the existing values when the column is changed,
+// e.g. table.Column("age", postgres.Using("integer", "trim(age)::integer")).Change(), it should wrap the other helpers.
+func Using(ttype, expression string) string {
+	return ttype + " using " + expression
+}
+
+// Storage returns the column type with a storage mode, plain, external, extended or main,
+// setting it when creating a table requires PostgreSQL 16 or later.
+func Storage(ttype, storage string) string {
+	return ttype + " storage " + storage
+}
+
+// Interval returns the column type of an interval that stores only the fields, e.g. Interval("year to month")
+// returns interval year to month and Interval("day to second", 3) returns interval day to second(3), the precision
+// only applies to the fields that end with second.
+func Interval(fields string, precision ...int) string {
+	ttype := "interval"
+	if fields != "" {
+		ttype += " " + fields
+	}
+	if len(precision) > 0 {
+		ttype += fmt.Sprintf("(%d)", precision[0])
+	}
+
+	return ttype
+}
+
+// parseArrayType splits a column type like "integer[][]" into its element type and dimensions.
+func parseArrayType(ttype string) (string, int) {
+	var dimensions int
+	for strings.HasSuffix(ttype, arraySuffix) {
+		ttype = strings.TrimSuffix(ttype, arraySuffix)
+		dimensions++
+	}
+
+	return ttype, dimensions
+}
+
+// parseColumnAttributes splits a column type like "text collate C compression lz4" into its type and attributes.
+func parseColumnAttributes(ttype string) (string, columnAttributes) {
+	var attributes columnAttributes
+	if before, using, ok := strings.Cut(ttype, " using "); ok {
+		ttype = before
+		attributes.using = using
+	}
+
+	for {
+		matches := columnAttributeRegex.FindStringSubmatch(ttype)
+		if len(matches) == 0 {
+			return ttype, attributes
+		}
+
+		ttype = matches[1]
+		switch matches[2] {
+		case "collate":
+			attributes.collation = matches[3]
+		case "compression":
+			attributes.compression = matches[3]
+		case "storage":
+			attributes.storage = matches[3]
+		}
+	}
+}
+
+// parseIntervalFields splits a column type like "interval day to second(3)" into its fields and precision.
+func parseIntervalFields(ttype string) (string, []int, bool) {
+	matches := intervalFieldsRegex.FindStringSubmatch(ttype)
+	if len(matches) == 0 {
+		return "", nil, false
+	}
+	if matches[2] == "" {
+		return matches[1], nil, true
+	}
+
+	precision, _ := strconv.Atoi(matches[2])
+
+	return matches[1], []int{precision}, true
+}
+
+// parseSQLType splits a PostgreSQL type like "timestamp(3) with time zone" into its internal name, modifiers and dimensions.
+func parseSQLType(ttype string) (string, []int, int) {
+	ttype, dimensions := parseArrayType(strings.ToLower(strings.TrimSpace(ttype)))
+
+	var modifiers []int
+	if matches := sqlTypeModifierRegex.FindStringSubmatch(ttype); len(matches) == 2 {
+		for _, modifier := range strings.Split(matches[1], ",") {
+			value, _ := strconv.Atoi(strings.TrimSpace(modifier))
+			modifiers = append(modifiers, value)
+		}
+		ttype = sqlTypeModifierRegex.ReplaceAllString(ttype, "")
+	}
+
+	if alias, ok := typeAliases[ttype]; ok {
+		ttype = alias
+	}
+
+	return ttype, modifiers, dimensions
+}
+
+// parseTypeModifiers splits a column type like "decimal(10, 2)" into its name and modifiers.
+func parseTypeModifiers(ttype string) (string, []int, bool) {
+	matches := typeModifierRegex.FindStringSubmatch(ttype)
+	if len(matches) == 0 {
+		return ttype, nil, false
+	}
+
+	var modifiers []int
+	for _, match := range matches[2:] {
+		if match == "" {
+			continue
+		}
+		modifier, _ := strconv.Atoi(match)
+		modifiers = append(modifiers, modifier)
+	}
+
+	return matches[1], modifiers, true
+}
+
+// requiresRewrite reports whether changing the type of a column from one PostgreSQL type to another rewrites
+// the table, only binary coercible types and relaxed modifiers can be changed without a rewrite. A change
+// between timestamp and timestamptz is treated as a rewrite, it only skips it when the time zone is UTC.
+func requiresRewrite(from, to string) bool {
+	fromName, fromModifiers, fromDimensions := parseSQLType(from)
+	toName, toModifiers, toDimensions := parseSQLType(to)
+	if fromDimensions != toDimensions {
+		return true
+	}
+
+	if fromName != toName {
+		switch {
+		case fromName == "varchar" && toName == "text", fromName == "cidr" && toName == "inet":
+			return false
+		case fromName == "text" && toName == "varchar":
+			return len(toModifiers) > 0
+		}
+
+		return true
+	}
+
+	switch fromName {
+	case "varchar", "varbit":
+		return len(toModifiers) > 0 && (len(fromModifiers) == 0 || toModifiers[0] < fromModifiers[0])
+	case "numeric":
+		if len(toModifiers) == 0 {
+			return false
+		}
+		if len(fromModifiers) == 0 {
+			return true
+		}
+
+		return toModifiers[0] < fromModifiers[0] || typeModifier(toModifiers, 1, 0) != typeModifier(fromModifiers, 1, 0)
+	case "interval", "time", "timetz", "timestamp", "timestamptz":
+		return typeModifier(toModifiers, 0, 6) < typeModifier(fromModifiers, 0, 6)
+	}
+
+	return !slices.Equal(fromModifiers, toModifiers)
+}
+
+func typeModifier(modifiers []int, index, def int) int {
+	if len(modifiers) > index {
+		return modifiers[index]
+	}
+
+	return def
+}
+
+func spatialType(ttype, subtype string, srid int) string {
+	if subtype == "" {
+		return ttype
+	}
+	if srid > 0 {
+		return fmt.Sprintf("%s(%s, %d)", ttype, subtype, srid)
+	}
+
+	return fmt.Sprintf("%s(%s)", ttype, subtype)
+}
+
+// attributedColumn carries the attributes of a column type to the modifiers of the grammar.
+type attributedColumn struct {
+	driver.ColumnDefinition
+	attributes columnAttributes
+}
+
+type columnAttributes struct {
+	collation   string
+	compression string
+	storage     string
+	using       string
+}
+
+func vectorType(ttype string, dimensions int) string {
+	if dimensions > 0 {
+		return fmt.Sprintf("%s(%d)", ttype, dimensions)
+	}
+
+	return ttype
+}
+
+// vectorLiteral converts a Go slice to a pgvector literal, e.g. []float32{1, 2} to [1,2], other values are bound as they are.
+func vectorLiteral(value any) any {
+	var elements []string
+	switch vector := value.(type) {
+	case []float32:
+		for _, element := range vector {
+			elements = append(elements, strconv.FormatFloat(float64(element), 'f', -1, 32))
+		}
+	case []float64:
+		for _, element := range vector {
+			elements = append(elements, strconv.FormatFloat(element, 'f', -1, 64))
+		}
+	default:
+		return value
+	}
+
+	return "[" + strings.Join(elements, ",") + "]"
+}
+
+// typedColumn overrides the type of a column, so the Type* methods of the grammar can render the element
+// type of an array column and the modifiers of a type like "bit(8)" the same way they render a plain column.
+type typedColumn struct {
+	driver.ColumnDefinition
+	ttype     string
+	modifiers []int
+	// fields are the fields of an interval, e.g. year to month.
+	fields string
+	array  bool
+}
+
+func (r *typedColumn) GetAutoIncrement() bool {
+	if r.array {
+		return false
+	}
+
+	return r.ColumnDefinition.GetAutoIncrement()
+}
+
+func (r *typedColumn) GetLength() int {
+	if len(r.modifiers) > 0 {
+		return r.modifiers[0]
+	}
+
+	return r.ColumnDefinition.GetLength()
+}
+
+func (r *typedColumn) GetPlaces() int {
+	if len(r.modifiers) > 1 {
+		return r.modifiers[1]
+	}
+
+	return r.ColumnDefinition.GetPlaces()
+}
+
+func (r *typedColumn) GetPrecision() int {
+	if len(r.modifiers) > 0 {
+		return r.modifiers[0]
+	}
+
+	return r.ColumnDefinition.GetPrecision()
+}
+
+func (r *typedColumn) GetTotal() int {
+	if len(r.modifiers) > 0 {
+		return r.modifiers[0]
+	}
+
+	return r.ColumnDefinition.GetTotal()
+}
+
+func (r *typedColumn) GetType() string {
+	return r.ttype
+}
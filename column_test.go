@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArray(t *testing.T) {
+	assert.Equal(t, "string[]", Array("string"))
+	assert.Equal(t, "integer[][]", Array("integer", 2))
+}
+
+func TestParseArrayType(t *testing.T) {
+	tests := []struct {
+		ttype              string
+		expectedType       string
+		expectedDimensions int
+	}{
+		{"string", "string", 0},
+		{"uuid[]", "uuid", 1},
+		{"integer[][]", "integer", 2},
+	}
+
+	for _, test := range tests {
+		ttype, dimensions := parseArrayType(test.ttype)
+		assert.Equal(t, test.expectedType, ttype)
+		assert.Equal(t, test.expectedDimensions, dimensions)
+	}
+}
+
+func TestParseTypeModifiers(t *testing.T) {
+	tests := []struct {
+		ttype             string
+		expectedName      string
+		expectedModifiers []int
+		expectedOk        bool
+	}{
+		{"inet", "inet", nil, false},
+		{"bit(8)", "bit", []int{8}, true},
+		{"decimal(10, 2)", "decimal", []int{10, 2}, true},
+		{"interval day to second(3)", "interval day to second(3)", nil, false},
+	}
+
+	for _, test := range tests {
+		name, modifiers, ok := parseTypeModifiers(test.ttype)
+		assert.Equal(t, test.expectedName, name)
+		assert.Equal(t, test.expectedModifiers, modifiers)
+		assert.Equal(t, test.expectedOk, ok)
+	}
+}
+
+func TestSpatialTypes(t *testing.T) {
+	assert.Equal(t, "geometry", Geometry(""))
+	assert.Equal(t, "geometry(point)", Geometry("point"))
+	assert.Equal(t, "geometry(polygon, 3857)", Geometry("polygon", 3857))
+	assert.Equal(t, "geography(point, 4326)", Geography("point"))
+	assert.Equal(t, "geography(linestring, 4269)", Geography("linestring", 4269))
+}
+
+func TestVectorTypes(t *testing.T) {
+	assert.Equal(t, "vector", Vector(0))
+	assert.Equal(t, "vector(1536)", Vector(1536))
+	assert.Equal(t, "halfVector(3072)", HalfVector(3072))
+	assert.Equal(t, "sparseVector(30000)", SparseVector(30000))
+}
+
+func TestVectorLiteral(t *testing.T) {
+	assert.Equal(t, "[1,0.5,-2]", vectorLiteral([]float32{1, 0.5, -2}))
+	assert.Equal(t, "[0.1,0.25]", vectorLiteral([]float64{0.1, 0.25}))
+	assert.Equal(t, "{1:1,3:2}/5", vectorLiteral("{1:1,3:2}/5"))
+}
+
+func TestColumnAttributes(t *testing.T) {
+	assert.Equal(t, "string collate und-x-icu", Collate("string", "und-x-icu"))
+	assert.Equal(t, "jsonb compression lz4", Compression("jsonb", "lz4"))
+	assert.Equal(t, "text storage external", Storage("text", "external"))
+	assert.Equal(t, "integer using trim(age)::integer", Using("integer", "trim(age)::integer"))
+	assert.Equal(t, "interval year to month", Interval("year to month"))
+	assert.Equal(t, "interval day to second(3)", Interval("day to second", 3))
+	assert.Equal(t, "interval(6)", Interval("", 6))
+}
+
+func TestParseColumnAttributes(t *testing.T) {
+	ttype, attributes := parseColumnAttributes("string[] collate en_US.utf8 compression lz4")
+	assert.Equal(t, "string[]", ttype)
+	assert.Equal(t, columnAttributes{collation: "en_US.utf8", compression: "lz4"}, attributes)
+
+	ttype, attributes = parseColumnAttributes("text collate C using coalesce(name, 'unknown')")
+	assert.Equal(t, "text", ttype)
+	assert.Equal(t, columnAttributes{collation: "C", using: "coalesce(name, 'unknown')"}, attributes)
+
+	ttype, attributes = parseColumnAttributes("timestamp with time zone")
+	assert.Equal(t, "timestamp with time zone", ttype)
+	assert.Equal(t, columnAttributes{}, attributes)
+}
+
+func TestParseSQLType(t *testing.T) {
+	tests := []struct {
+		ttype      string
+		name       string
+		modifiers  []int
+		dimensions int
+	}{
+		{"integer", "int4", nil, 0},
+		{"character varying(255)", "varchar", []int{255}, 0},
+		{"numeric(10, 2)", "numeric", []int{10, 2}, 0},
+		{"timestamp(3) with time zone", "timestamptz", []int{3}, 0},
+		{"TEXT[]", "text", nil, 1},
+		{"jsonb", "jsonb", nil, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ttype, func(t *testing.T) {
+			name, modifiers, dimensions := parseSQLType(test.ttype)
+			assert.Equal(t, test.name, name)
+			assert.Equal(t, test.modifiers, modifiers)
+			assert.Equal(t, test.dimensions, dimensions)
+		})
+	}
+}
+
+func TestRequiresRewrite(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{"character varying(100)", "varchar(255)", false},
+		{"character varying(255)", "varchar(100)", true},
+		{"character varying(255)", "varchar", false},
+		{"character varying", "varchar(255)", true},
+		{"character varying(255)", "text", false},
+		{"text", "varchar", false},
+		{"text", "varchar(255)", true},
+		{"numeric(8,2)", "decimal(10, 2)", false},
+		{"numeric(8,2)", "decimal(10, 3)", true},
+		{"numeric(8,2)", "numeric", false},
+		{"timestamp(0) without time zone", "timestamp(3) without time zone", false},
+		{"timestamp without time zone", "timestamp(3) without time zone", true},
+		{"timestamp(0) without time zone", "timestamp(0) with time zone", true},
+		{"cidr", "inet", false},
+		{"integer", "bigint", true},
+		{"text", "jsonb", true},
+		{"text", "text[]", true},
+		{"bit(8)", "bit(8)", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+" to "+test.to, func(t *testing.T) {
+			assert.Equal(t, test.expected, requiresRewrite(test.from, test.to))
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package contracts
+
+// Check Used to define a check constraint, e.g. CONSTRAINT name CHECK (expression)
+type Check struct {
+	Name       string
+	Expression string
+	// NotValid skips checking the existing rows when the constraint is added to a table or a domain,
+	// they can be checked later via Grammar.CompileValidateConstraint.
+	NotValid bool
+}
+
+// ColumnTypeChange Used to change the type of a column, e.g. ALTER TABLE name ALTER COLUMN column TYPE type USING expression
+type ColumnTypeChange struct {
+	Table  string
+	Column string
+	// From is the current type of the column, e.g. the Type of Schema.GetColumns, it's used to pick a USING
+	// expression for the known conversions and to tell whether the change rewrites the table.
+	From string
+	// Type is the new type, it can be a column type of the grammar, e.g. string(255), or a PostgreSQL type.
+	Type string
+	// Using is the expression that converts the existing values, it overrides the automatic one.
+	Using string
+}
+
+// Domain Used to create a domain, e.g. CREATE DOMAIN name AS type DEFAULT value NOT NULL CHECK (expression)
+type Domain struct {
+	// Type is the base type of the domain, it can be a column type of the grammar, e.g. string(255), or a PostgreSQL type.
+	Type      string
+	Name      string
+	Collation string
+	Default   any
+	Checks    []Check
+	NotNull   bool
+}
+
+// Extension Used to manage an extension, e.g. CREATE EXTENSION IF NOT EXISTS name SCHEMA schema VERSION 'version',
+// it's also the result of Grammar.CompileExtensions.
+type Extension struct {
+	Name    string
+	Schema  string
+	Version string
+	Cascade bool
+}
+
+// ForeignKey Used to add a foreign key, e.g. ALTER TABLE name ADD CONSTRAINT name FOREIGN KEY (columns)
+// REFERENCES table (columns) MATCH FULL DEFERRABLE NOT VALID
+type ForeignKey struct {
+	Table      string
+	Name       string
+	Columns    []string
+	On         string
+	References []string
+	OnDelete   string
+	OnUpdate   string
+	// Match is full or simple.
+	Match              string
+	Deferrable         *bool
+	InitiallyImmediate *bool
+	// NotValid skips checking the existing rows, they can be checked later via Grammar.CompileValidateConstraint.
+	NotValid bool
+}
+
+// FullTextSearch Used to search the columns of a full text index, e.g. to_tsvector('english', column) @@ websearch_to_tsquery('english', ?),
+// the query is bound to the placeholder.
+type FullTextSearch struct {
+	Columns []string
+	// Language is the text search configuration, english by default, it must be the one of the index to use it.
+	Language string
+	// Mode is websearch, plain or phrase, websearch by default.
+	Mode string
+	// Weights are the weights of the columns when ranking, e.g. {"title": "A", "body": "B"}.
+	Weights map[string]string
+	// Normalization is the normalization of the rank, e.g. 32 to scale it into the range 0 to 1.
+	Normalization int
+	// CoverDensity ranks with ts_rank_cd instead of ts_rank.
+	CoverDensity bool
+}
+
+// Function Used to create a function, e.g. CREATE OR REPLACE FUNCTION name(arguments) RETURNS type LANGUAGE plpgsql AS body
+type Function struct {
+	Name string
+	// Arguments are the arguments of the function, e.g. a integer, b text.
+	Arguments string
+	Returns   string
+	// Language is the language of the body, plpgsql by default.
+	Language string
+	Body     string
+	// Volatility is immutable, stable or volatile.
+	Volatility      string
+	SecurityDefiner bool
+	OrReplace       bool
+	Cascade         bool
+}
+
+// Grant Used to grant or revoke privileges, e.g. GRANT SELECT, INSERT ON TABLE name TO role WITH GRANT OPTION
+type Grant struct {
+	// Privileges are select, insert, update, delete, usage, execute and so on, all privileges when empty.
+	Privileges []string
+	// ObjectType is table, sequence, schema or function, the objects are all the objects of the type in the
+	// given schemas when it's like all tables in schema.
+	ObjectType string
+	// Objects are the names of the objects, the names of functions contain their arguments, e.g. add(integer, integer).
+	Objects         []string
+	Roles           []string
+	WithGrantOption bool
+	// Cascade is only used when revoking privileges.
+	Cascade bool
+}
+
+// Index Used to create an index with an operator class or storage parameters,
+// e.g. CREATE INDEX CONCURRENTLY name ON table USING gin (column gin_trgm_ops) WITH (fastupdate = off) WHERE predicate
+type Index struct {
+	Table   string
+	Name    string
+	Columns []string
+	// Algorithm is the index method, e.g. gin, gist, or hnsw and ivfflat of pgvector.
+	Algorithm string
+	// OperatorClass is the operator class of the columns, e.g. gin_trgm_ops or vector_cosine_ops.
+	OperatorClass string
+	// With are the storage parameters of the index, e.g. {"fastupdate": "off"} or {"m": 16, "ef_construction": 64}.
+	With map[string]any
+	// Where is the predicate of a partial index.
+	Where        string
+	Unique       bool
+	Concurrently bool
+}
+
+// MaterializedView Used to create a materialized view, e.g. CREATE MATERIALIZED VIEW name AS query WITH DATA
+type MaterializedView struct {
+	Name  string
+	Query string
+	// NoData creates the view WITH NO DATA, it can't be queried until it's refreshed.
+	NoData bool
+}
+
+// Policy Used to create or alter a row level security policy, e.g. CREATE POLICY name ON table AS PERMISSIVE FOR SELECT TO role USING (expression)
+type Policy struct {
+	Name  string
+	Table string
+	// As is permissive or restrictive, only used when creating a policy.
+	As string
+	// Command is all, select, insert, update or delete, only used when creating a policy.
+	Command   string
+	Roles     []string
+	Using     string
+	WithCheck string
+}
+
+// RangeType Used to create a custom range type, e.g. CREATE TYPE name AS RANGE (SUBTYPE = subtype)
+type RangeType struct {
+	Name               string
+	Subtype            string
+	SubtypeOpClass     string
+	Collation          string
+	Canonical          string
+	SubtypeDiff        string
+	MultirangeTypeName string
+}
+
+// Role Used to create or alter a role, e.g. CREATE ROLE name WITH LOGIN PASSWORD 'password',
+// the options that are nil are not changed.
+type Role struct {
+	Name            string
+	Login           *bool
+	Superuser       *bool
+	CreateDB        *bool
+	CreateRole      *bool
+	Inherit         *bool
+	Replication     *bool
+	BypassRLS       *bool
+	ConnectionLimit *int64
+	Password        *string
+	ValidUntil      string
+	// InRoles are the roles the new role is added to, only used when creating a role.
+	InRoles []string
+}
+
+// Schema Used to manage a schema, e.g. CREATE SCHEMA IF NOT EXISTS name AUTHORIZATION role,
+// it's also the result of Grammar.CompileSchemas.
+type Schema struct {
+	Name          string
+	Authorization string
+	Comment       string
+	IfNotExists   bool
+	Cascade       bool
+}
+
+// Sequence Used to create or alter a sequence, e.g. CREATE SEQUENCE name INCREMENT BY 1 START WITH 1 OWNED BY table.column,
+// the options that are nil are not changed.
+type Sequence struct {
+	Name string
+	// As is the data type of the sequence, e.g. bigint.
+	As        string
+	Increment *int64
+	MinValue  *int64
+	MaxValue  *int64
+	Start     *int64
+	// Restart is only used when altering a sequence.
+	Restart *int64
+	Cache   *int64
+	Cycle   *bool
+	// OwnedBy is the table.column the sequence belongs to, or none.
+	OwnedBy     string
+	IfNotExists bool
+	Cascade     bool
+}
+
+// ForeignKeyOptions Used to add the foreign keys of Blueprint.Foreign, they can be set for a connection via
+// database.connections.%s.foreign_keys.
+type ForeignKeyOptions struct {
+	// Match is full or simple.
+	Match string
+	// NotValid adds the foreign keys without scanning the existing rows, which blocks the writes to the table,
+	// the rows can be checked in a later migration via Grammar.CompileValidateConstraints.
+	NotValid bool
+}
+
+// TableOptions Used to create a table with options, e.g. CREATE UNLOGGED TABLE name (columns) WITH (fillfactor = 70),
+// they can be set for the tables of a connection via database.connections.%s.table_options, which is a
+// map[string]TableOptions or a map[string]any of maps with the snake case names of the fields keyed by the table
+// name without prefix, or for a table of a migration via Postgres.CreateTable.
+type TableOptions struct {
+	IfNotExists bool
+	Unlogged    bool
+	Temporary   bool
+	// OnCommit is preserve rows, delete rows or drop, only used for temporary tables.
+	OnCommit string
+	Inherits []string
+	// With are the storage parameters, e.g. fillfactor or autovacuum_vacuum_scale_factor.
+	With       map[string]any
+	Tablespace string
+}
+
+// TextSearchConfiguration Used to create a text search configuration, e.g. CREATE TEXT SEARCH CONFIGURATION name (COPY = english)
+type TextSearchConfiguration struct {
+	Name string
+	// Copy is the configuration to copy, e.g. english.
+	Copy string
+	// Mappings are the dictionaries of the token types, e.g. {"hword, hword_part, word": {"unaccent", "english_stem"}}.
+	Mappings map[string][]string
+}
+
+// Trigger Used to create a trigger, e.g. CREATE TRIGGER name BEFORE UPDATE ON table FOR EACH ROW EXECUTE FUNCTION function()
+type Trigger struct {
+	Name  string
+	Table string
+	// Timing is before, after or instead of.
+	Timing string
+	// Events are insert, update, delete, truncate or update of column.
+	Events []string
+	// ForEach is row or statement.
+	ForEach   string
+	When      string
+	Function  string
+	Arguments []string
+	OrReplace bool
+}
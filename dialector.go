@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"time"
+
+	"github.com/goravel/framework/contracts/log"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+const (
+	// lockNotAvailable is the SQLSTATE of a statement that fails to acquire a lock within lock_timeout.
+	lockNotAvailable = "55P03"
+	// maxRetryDelay caps the backoff between the attempts of a statement.
+	maxRetryDelay = 10 * time.Second
+	retryDelay    = 200 * time.Millisecond
+)
+
+var (
+	schemaStatementRegex = regexp.MustCompile(`(?is)^\s*(alter|comment|create|do|drop|grant|refresh|revoke)\b`)
+	// Statements like CREATE INDEX CONCURRENTLY or CREATE DATABASE can't run inside of a transaction, which the
+	// statements that are sent along with SET LOCAL run in, so they are run as they are, without the timeouts and
+	// without the retries. A session-level SET would leak to the other users of the pooled connection, and a
+	// concurrent index build that is canceled by a timeout leaves an invalid index behind, which a retry can't fix:
+	// set lock_timeout for these statements in the migration itself and drop the invalid index when it fails.
+	nonTransactionalStatementRegex = regexp.MustCompile(`(?is)\bconcurrently\b|^\s*(?:(?:alter|create|drop)\s+(?:database|subscription|tablespace)|alter\s+system)\b`)
+)
+
+// Dialector runs the schema statements, e.g. the ones of the migrations, with SET LOCAL lock_timeout and
+// statement_timeout, so a statement that waits for a long transaction doesn't block all the queries of the
+// table behind it. The statements that fail to acquire a lock in time are retried with a jittered backoff.
+// The raw statements are told apart by their first keyword, the ones that can't run inside of a transaction,
+// e.g. CREATE INDEX CONCURRENTLY or CREATE DATABASE, are run as they are without the timeouts.
+type Dialector struct {
+	*postgres.Dialector
+	log              log.Log
+	lockTimeout      string
+	statementTimeout string
+	attempts         int
+}
+
+func NewDialector(dialector *postgres.Dialector, log log.Log, lockTimeout, statementTimeout string, attempts int) *Dialector {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &Dialector{
+		Dialector:        dialector,
+		log:              log,
+		lockTimeout:      lockTimeout,
+		statementTimeout: statementTimeout,
+		attempts:         attempts,
+	}
+}
+
+func (r *Dialector) Initialize(db *gorm.DB) error {
+	if err := r.Dialector.Initialize(db); err != nil {
+		return err
+	}
+
+	return db.Callback().Raw().Replace("gorm:raw", r.exec)
+}
+
+func (r *Dialector) exec(db *gorm.DB) {
+	sql := db.Statement.SQL.String()
+	if db.Error != nil || db.DryRun || len(db.Statement.Vars) > 0 ||
+		!schemaStatementRegex.MatchString(sql) || nonTransactionalStatementRegex.MatchString(sql) {
+		callbacks.RawExec(db)
+		return
+	}
+
+	// A statement of a transaction can't be retried, the transaction is aborted once it fails.
+	_, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter)
+	for attempt := 1; ; attempt++ {
+		result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, r.settings(sql, inTransaction))
+		if err == nil {
+			db.RowsAffected, _ = result.RowsAffected()
+			if db.Statement.Result != nil {
+				db.Statement.Result.Result = result
+				db.Statement.Result.RowsAffected = db.RowsAffected
+			}
+
+			return
+		}
+
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != lockNotAvailable {
+			db.AddError(err)
+			return
+		}
+		if inTransaction || attempt >= r.attempts {
+			if r.log != nil {
+				r.log.Errorf("[%s] attempt %d/%d of %s failed to acquire a lock within %s, giving up", Name, attempt, r.attempts, sql, r.lockTimeout)
+			}
+			db.AddError(err)
+			return
+		}
+
+		delay := backoff(attempt)
+		if r.log != nil {
+			r.log.Warningf("[%s] attempt %d/%d of %s failed to acquire a lock within %s, retrying in %s", Name, attempt, r.attempts, sql, r.lockTimeout, delay)
+		}
+
+		select {
+		case <-db.Statement.Context.Done():
+			db.AddError(db.Statement.Context.Err())
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// settings sends the statement along with SET LOCAL, the statements of a query that is sent as a whole run in
+// an implicit transaction, which SET LOCAL applies to and which is rolled back when the statement fails. Inside of
+// a transaction, SET LOCAL would apply to the rest of the transaction, so the previous values, e.g. the ones that
+// the transaction sets itself, are saved in custom settings before the statement and restored after it.
+func (r *Dialector) settings(sql string, inTransaction bool) string {
+	var saves, settings, restores string
+	for _, setting := range []struct{ name, value string }{
+		{"lock_timeout", r.lockTimeout},
+		{"statement_timeout", r.statementTimeout},
+	} {
+		if setting.value == "" {
+			continue
+		}
+
+		saves += fmt.Sprintf("select set_config('goravel.%s', current_setting('%s'), true); ", setting.name, setting.name)
+		settings += fmt.Sprintf("set local %s = '%s'; ", setting.name, setting.value)
+		restores += fmt.Sprintf("; select set_config('%s', current_setting('goravel.%s'), true)", setting.name, setting.name)
+	}
+	if !inTransaction {
+		return settings + sql
+	}
+
+	return saves + settings + sql + restores
+}
+
+// backoff doubles the delay of each attempt and picks a random delay between its half and itself,
+// so the migrations of several instances don't retry at the same time.
+func backoff(attempt int) time.Duration {
+	delay := retryDelay << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type DialectorTestSuite struct {
+	suite.Suite
+	mockLog *mockslog.Log
+	pool    *connPool
+}
+
+func TestDialectorTestSuite(t *testing.T) {
+	suite.Run(t, new(DialectorTestSuite))
+}
+
+func (s *DialectorTestSuite) SetupTest() {
+	s.mockLog = mockslog.NewLog(s.T())
+	s.pool = &connPool{}
+}
+
+func (s *DialectorTestSuite) TestExec() {
+	dialector := NewDialector(&postgres.Dialector{}, s.mockLog, "5s", "1min", 3)
+
+	db := s.db(`alter table "users" add column "name" varchar(255)`)
+	dialector.exec(db)
+	s.NoError(db.Error)
+	s.Equal([]string{`set local lock_timeout = '5s'; set local statement_timeout = '1min'; alter table "users" add column "name" varchar(255)`}, s.pool.statements)
+}
+
+func (s *DialectorTestSuite) TestExecSkipsOtherStatements() {
+	dialector := NewDialector(&postgres.Dialector{}, s.mockLog, "5s", "", 3)
+
+	for _, statement := range []string{
+		`update "users" set "name" = 'goravel'`,
+		`create index concurrently "users_name_index" on "users" ("name")`,
+		`create database "goravel"`,
+		`drop tablespace "archive"`,
+		`alter system set work_mem = '64MB'`,
+	} {
+		s.pool.statements = nil
+		db := s.db(statement)
+		dialector.exec(db)
+		s.NoError(db.Error)
+		s.Equal([]string{statement}, s.pool.statements)
+	}
+}
+
+func (s *DialectorTestSuite) TestExecRetries() {
+	dialector := NewDialector(&postgres.Dialector{}, s.mockLog, "5s", "", 3)
+	lockErr := &pgconn.PgError{Code: lockNotAvailable, Message: "canceling statement due to lock timeout"}
+
+	s.pool.errors = []error{lockErr, nil}
+	s.mockLog.EXPECT().Warningf(mock.Anything, Name, 1, 3, `drop table "users"`, "5s", mock.Anything).Once()
+	db := s.db(`drop table "users"`)
+	dialector.exec(db)
+	s.NoError(db.Error)
+	s.Len(s.pool.statements, 2)
+
+	// It gives up after the last attempt.
+	s.pool.statements = nil
+	s.pool.errors = []error{lockErr, lockErr, lockErr}
+	s.mockLog.EXPECT().Warningf(mock.Anything, Name, mock.Anything, 3, `drop table "users"`, "5s", mock.Anything).Twice()
+	s.mockLog.EXPECT().Errorf(mock.Anything, Name, 3, 3, `drop table "users"`, "5s").Once()
+	db = s.db(`drop table "users"`)
+	dialector.exec(db)
+	s.ErrorIs(db.Error, lockErr)
+	s.Len(s.pool.statements, 3)
+
+	// The other errors aren't retried.
+	s.pool.statements = nil
+	s.pool.errors = []error{&pgconn.PgError{Code: "42P01"}}
+	db = s.db(`drop table "users"`)
+	dialector.exec(db)
+	s.Error(db.Error)
+	s.Len(s.pool.statements, 1)
+}
+
+func (s *DialectorTestSuite) TestExecInTransaction() {
+	dialector := NewDialector(&postgres.Dialector{}, s.mockLog, "5s", "1min", 3)
+	lockErr := &pgconn.PgError{Code: lockNotAvailable, Message: "canceling statement due to lock timeout"}
+
+	db := s.db(`alter table "users" add column "name" varchar(255)`)
+	db.Statement.ConnPool = &txPool{connPool: s.pool}
+	dialector.exec(db)
+	s.NoError(db.Error)
+	s.Equal([]string{`select set_config('goravel.lock_timeout', current_setting('lock_timeout'), true); ` +
+		`select set_config('goravel.statement_timeout', current_setting('statement_timeout'), true); ` +
+		`set local lock_timeout = '5s'; set local statement_timeout = '1min'; alter table "users" add column "name" varchar(255); ` +
+		`select set_config('lock_timeout', current_setting('goravel.lock_timeout'), true); ` +
+		`select set_config('statement_timeout', current_setting('goravel.statement_timeout'), true)`}, s.pool.statements)
+
+	// A statement of a transaction isn't retried.
+	s.pool.statements = nil
+	s.pool.errors = []error{lockErr}
+	s.mockLog.EXPECT().Errorf(mock.Anything, Name, 1, 3, `drop table "users"`, "5s").Once()
+	db = s.db(`drop table "users"`)
+	db.Statement.ConnPool = &txPool{connPool: s.pool}
+	dialector.exec(db)
+	s.ErrorIs(db.Error, lockErr)
+	s.Len(s.pool.statements, 1)
+}
+
+func (s *DialectorTestSuite) TestBackoff() {
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := backoff(attempt)
+		s.GreaterOrEqual(delay, min(retryDelay<<(attempt-1), maxRetryDelay)/2)
+		s.LessOrEqual(delay, maxRetryDelay)
+	}
+}
+
+func (s *DialectorTestSuite) db(statement string) *gorm.DB {
+	db := &gorm.DB{Config: &gorm.Config{}}
+	db.Statement = &gorm.Statement{DB: db, ConnPool: s.pool, Context: context.Background()}
+	db.Statement.SQL.WriteString(statement)
+
+	return db
+}
+
+type connPool struct {
+	errors     []error
+	statements []string
+}
+
+func (r *connPool) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	r.statements = append(r.statements, query)
+	if len(r.errors) > 0 {
+		err := r.errors[0]
+		r.errors = r.errors[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return driverResult(0), nil
+}
+
+func (r *connPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, nil
+}
+
+func (r *connPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (r *connPool) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+type txPool struct {
+	*connPool
+}
+
+func (r *txPool) Commit() error {
+	return nil
+}
+
+func (r *txPool) Rollback() error {
+	return nil
+}
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (r driverResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
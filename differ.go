@@ -0,0 +1,771 @@
+package postgres
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goravel/framework/support/collect"
+
+	"github.com/goravel/postgres/contracts"
+)
+
+// Snapshot is the state of the tables and of the types of a connection, it's returned by
+// Dumper.Snapshot and read from a file by ReadSnapshot. ServerVersion is the version of the server as a
+// number, e.g. 170002, it's zero when it's unknown.
+type Snapshot struct {
+	ServerVersion int             `json:"server_version"`
+	Tables        []SnapshotTable `json:"tables"`
+	Types         []Type          `json:"types"`
+}
+
+// SnapshotTable is a table of a Snapshot, the names of the table and of the tables its foreign keys
+// reference are without the prefix. The names of the indexes and of the constraints and the definitions of
+// the indexes keep it, so only the snapshots of connections with the same prefix can be compared.
+type SnapshotTable struct {
+	Schema      string            `json:"schema"`
+	Name        string            `json:"name"`
+	Comment     string            `json:"comment"`
+	Columns     []Column          `json:"columns"`
+	Checks      []contracts.Check `json:"checks"`
+	Indexes     []Index           `json:"indexes"`
+	ForeignKeys []ForeignKey      `json:"foreign_keys"`
+	Triggers    []Trigger         `json:"triggers"`
+}
+
+// SchemaChange is a difference between two snapshots and the statements that reconcile it, the statements
+// are empty when the difference can't be reconciled, e.g. a value that is removed from an enum.
+type SchemaChange struct {
+	// Table is the name of the table without the prefix, it's empty for the changes of the types.
+	Table       string
+	Description string
+	Statements  []string
+}
+
+// SchemaDiff is the result of Differ.Diff, the Destructive changes drop data, e.g. the columns and the tables
+// that are removed, so they are reported separately and should be reviewed before they are applied.
+type SchemaDiff struct {
+	Changes     []SchemaChange
+	Destructive []SchemaChange
+}
+
+// Empty reports whether the snapshots are the same.
+func (r SchemaDiff) Empty() bool {
+	return len(r.Changes) == 0 && len(r.Destructive) == 0
+}
+
+// Statements returns the statements of the changes, followed by the ones of the destructive changes if destructive is true.
+func (r SchemaDiff) Statements(destructive bool) []string {
+	changes := r.Changes
+	if destructive {
+		changes = slices.Concat(r.Changes, r.Destructive)
+	}
+
+	var statements []string
+	for _, change := range changes {
+		statements = append(statements, change.Statements...)
+	}
+
+	return statements
+}
+
+// ReadSnapshot reads a snapshot that is written by Dumper.SnapshotToFile.
+func ReadSnapshot(path string) (*Snapshot, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// Differ compares two snapshots, e.g. a database and the snapshot file of another one, and returns the
+// statements that change the first one into the second one.
+type Differ struct {
+	grammar *Grammar
+}
+
+func NewDiffer(grammar *Grammar) *Differ {
+	return &Differ{
+		grammar: grammar,
+	}
+}
+
+// Diff returns the changes that turn from into to. The changes are ordered so they can be applied one by one:
+// the types are created before the tables, the tables before the indexes, the foreign keys and the triggers,
+// and the foreign keys are dropped before the destructive changes drop the columns and the tables they use.
+func (r *Differ) Diff(from, to *Snapshot) SchemaDiff {
+	var diff SchemaDiff
+
+	r.diffTypes(&diff, from.Types, to.Types)
+
+	var indexes, foreignKeys, triggers []SchemaChange
+	for _, table := range to.Tables {
+		current, ok := r.findTable(from.Tables, table)
+		if !ok {
+			current = SnapshotTable{Schema: table.Schema, Name: table.Name}
+			diff.Changes = append(diff.Changes, r.createTable(table))
+		} else {
+			r.diffTable(&diff, current, table, from.ServerVersion)
+		}
+
+		indexes = append(indexes, r.diffIndexes(current, table, !ok)...)
+		foreignKeys = append(foreignKeys, r.diffForeignKeys(current, table)...)
+		triggers = append(triggers, r.diffTriggers(current, table)...)
+	}
+	diff.Changes = slices.Concat(diff.Changes, indexes, foreignKeys, triggers)
+
+	// The foreign keys of the tables that are dropped are dropped first, the tables may reference each other.
+	var dropTables []SchemaChange
+	for _, table := range from.Tables {
+		if _, ok := r.findTable(to.Tables, table); ok {
+			continue
+		}
+
+		name := r.grammar.wrap.Table(r.qualifiedName(table))
+		for _, foreignKey := range table.ForeignKeys {
+			diff.Destructive = append(diff.Destructive, SchemaChange{
+				Table:       table.Name,
+				Description: fmt.Sprintf("drop foreign key %s of table %s", foreignKey.Name, table.Name),
+				Statements:  []string{r.dropConstraint(name, foreignKey.Name)},
+			})
+		}
+		dropTables = append(dropTables, SchemaChange{
+			Table:       table.Name,
+			Description: fmt.Sprintf("drop table %s", table.Name),
+			Statements:  []string{fmt.Sprintf("drop table %s", name)},
+		})
+	}
+	diff.Destructive = slices.Concat(diff.Destructive, dropTables)
+
+	r.dropTypes(&diff, from.Types, to.Types)
+
+	return diff
+}
+
+func (r *Differ) createTable(table SnapshotTable) SchemaChange {
+	name := r.grammar.wrap.Table(r.qualifiedName(table))
+	definitions := collect.Map(table.Columns, func(column Column, _ int) string {
+		return r.grammar.getColumnDefinition(column)
+	})
+	for _, check := range table.Checks {
+		check.NotValid = false
+		definitions = append(definitions, r.grammar.getCheck(check))
+	}
+	for _, index := range table.Indexes {
+		if index.Primary {
+			definitions = append(definitions, fmt.Sprintf("constraint %s primary key (%s)", r.grammar.wrap.Value(index.Name), r.grammar.wrap.Columnize(index.Columns)))
+		}
+	}
+
+	statements := []string{fmt.Sprintf("create table %s (%s)", name, strings.Join(definitions, ", "))}
+	if table.Comment != "" {
+		statements = append(statements, fmt.Sprintf("comment on table %s is %s", name, r.grammar.quoteString(table.Comment)))
+	}
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			statements = append(statements, r.commentOnColumn(name, column))
+		}
+	}
+
+	return SchemaChange{
+		Table:       table.Name,
+		Description: fmt.Sprintf("create table %s", table.Name),
+		Statements:  statements,
+	}
+}
+
+func (r *Differ) diffTable(diff *SchemaDiff, from, to SnapshotTable, serverVersion int) {
+	qualified := r.qualifiedName(to)
+	name := r.grammar.wrap.Table(qualified)
+
+	for _, column := range to.Columns {
+		current, ok := findByName(from.Columns, column.Name, func(column Column) string { return column.Name })
+		if !ok {
+			statements := []string{fmt.Sprintf("alter table %s add column %s", name, r.grammar.getColumnDefinition(column))}
+			if column.Comment != "" {
+				statements = append(statements, r.commentOnColumn(name, column))
+			}
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("add column %s.%s", to.Name, column.Name),
+				Statements:  statements,
+			})
+
+			continue
+		}
+
+		// A column can't become generated, and the expression of a generated column can only be changed on
+		// PostgreSQL 17 or later, so on the older servers the column is dropped and added again, which loses
+		// its values. The version of the server whose snapshot is from decides, an unknown version is older.
+		recreate := current.Generation == "" && column.Generation != ""
+		changeExpression := current.Generation != "" && column.Generation != "" && current.Generation != column.Generation
+		if recreate || changeExpression && serverVersion < 170000 {
+			statements := []string{
+				fmt.Sprintf("alter table %s drop column %s", name, r.grammar.wrap.Column(column.Name)),
+				fmt.Sprintf("alter table %s add column %s", name, r.grammar.getColumnDefinition(column)),
+			}
+			if column.Comment != "" {
+				statements = append(statements, r.commentOnColumn(name, column))
+			}
+			description := fmt.Sprintf("drop column %s.%s and add it again as a generated column", to.Name, column.Name)
+			if !recreate {
+				description = fmt.Sprintf("drop column %s.%s and add it again with the new generation expression, changing it requires PostgreSQL 17", to.Name, column.Name)
+			}
+			diff.Destructive = append(diff.Destructive, SchemaChange{
+				Table:       to.Name,
+				Description: description,
+				Statements:  statements,
+			})
+
+			continue
+		}
+
+		// The collation is changed with the type, the default collation of the type is restored when it's removed.
+		if current.Type != column.Type || current.Collation != column.Collation {
+			ttype := column.Type
+			if current.Collation != column.Collation {
+				ttype += " collate " + cmp.Or(column.Collation, "default")
+			}
+			sql, rewrite := r.grammar.CompileAlterColumnType(contracts.ColumnTypeChange{
+				Table:  qualified,
+				Column: column.Name,
+				From:   current.Type,
+				Type:   ttype,
+			})
+			description := fmt.Sprintf("change the type of column %s.%s from %s to %s", to.Name, column.Name, current.Type, column.Type)
+			if current.Type == column.Type {
+				description = fmt.Sprintf("change the collation of column %s.%s from %s to %s", to.Name, column.Name, cmp.Or(current.Collation, "default"), cmp.Or(column.Collation, "default"))
+			}
+			if rewrite {
+				description += ", it rewrites the table"
+			}
+			diff.Changes = append(diff.Changes, SchemaChange{Table: to.Name, Description: description, Statements: []string{sql}})
+		}
+
+		if current.Default != column.Default {
+			sql := fmt.Sprintf("alter table %s alter column %s drop default", name, r.grammar.wrap.Column(column.Name))
+			if column.Default != "" {
+				sql = fmt.Sprintf("alter table %s alter column %s set default %s", name, r.grammar.wrap.Column(column.Name), column.Default)
+			}
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("change the default of column %s.%s", to.Name, column.Name),
+				Statements:  []string{sql},
+			})
+		}
+
+		if current.Nullable != column.Nullable {
+			change := SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("make column %s.%s nullable", to.Name, column.Name),
+				Statements:  []string{fmt.Sprintf("alter table %s alter column %s drop not null", name, r.grammar.wrap.Column(column.Name))},
+			}
+			if !column.Nullable {
+				change.Description = fmt.Sprintf("make column %s.%s not null", to.Name, column.Name)
+				change.Statements = r.grammar.CompileSetNotNull(qualified, column.Name)
+			}
+			diff.Changes = append(diff.Changes, change)
+		}
+
+		if current.Identity != column.Identity {
+			sql := fmt.Sprintf("alter table %s alter column %s set generated %s", name, r.grammar.wrap.Column(column.Name), column.Identity)
+			if current.Identity == "" {
+				sql = fmt.Sprintf("alter table %s alter column %s add generated %s as identity", name, r.grammar.wrap.Column(column.Name), column.Identity)
+			} else if column.Identity == "" {
+				sql = fmt.Sprintf("alter table %s alter column %s drop identity if exists", name, r.grammar.wrap.Column(column.Name))
+			}
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("change the identity of column %s.%s", to.Name, column.Name),
+				Statements:  []string{sql},
+			})
+		}
+
+		// Set expression as requires PostgreSQL 17, the older servers recreate the column above.
+		if current.Generation != column.Generation {
+			sql := fmt.Sprintf("alter table %s alter column %s drop expression", name, r.grammar.wrap.Column(column.Name))
+			description := fmt.Sprintf("make column %s.%s a regular column", to.Name, column.Name)
+			if column.Generation != "" {
+				sql = fmt.Sprintf("alter table %s alter column %s set expression as (%s)", name, r.grammar.wrap.Column(column.Name), column.Generation)
+				description = fmt.Sprintf("change the generation expression of column %s.%s, it requires PostgreSQL 17", to.Name, column.Name)
+			}
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Table:       to.Name,
+				Description: description,
+				Statements:  []string{sql},
+			})
+		}
+
+		if current.Comment != column.Comment {
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("change the comment of column %s.%s", to.Name, column.Name),
+				Statements:  []string{r.commentOnColumn(name, column)},
+			})
+		}
+	}
+
+	for _, column := range from.Columns {
+		if _, ok := findByName(to.Columns, column.Name, func(column Column) string { return column.Name }); !ok {
+			diff.Destructive = append(diff.Destructive, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("drop column %s.%s", to.Name, column.Name),
+				Statements:  []string{fmt.Sprintf("alter table %s drop column %s", name, r.grammar.wrap.Column(column.Name))},
+			})
+		}
+	}
+
+	if from.Comment != to.Comment {
+		comment := "null"
+		if to.Comment != "" {
+			comment = r.grammar.quoteString(to.Comment)
+		}
+		diff.Changes = append(diff.Changes, SchemaChange{
+			Table:       to.Name,
+			Description: fmt.Sprintf("change the comment of table %s", to.Name),
+			Statements:  []string{fmt.Sprintf("comment on table %s is %s", name, comment)},
+		})
+	}
+
+	checkName := func(check contracts.Check) string { return check.Name }
+	for _, check := range from.Checks {
+		if current, ok := findByName(to.Checks, check.Name, checkName); !ok || current.Expression != check.Expression {
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("drop check %s of table %s", check.Name, to.Name),
+				Statements:  []string{r.dropConstraint(name, check.Name)},
+			})
+		}
+	}
+	for _, check := range to.Checks {
+		if current, ok := findByName(from.Checks, check.Name, checkName); !ok || current.Expression != check.Expression {
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("add check %s to table %s", check.Name, to.Name),
+				Statements:  []string{r.grammar.CompileAddCheck(qualified, check)},
+			})
+		}
+	}
+}
+
+// diffIndexes drops the indexes that are removed or changed and creates the ones that are added or changed,
+// the primary key of a table that is created is part of its create table statement.
+func (r *Differ) diffIndexes(from, to SnapshotTable, created bool) []SchemaChange {
+	qualified := r.qualifiedName(to)
+	indexName := func(index Index) string { return index.Name }
+
+	var changes []SchemaChange
+	for _, index := range from.Indexes {
+		if current, ok := findByName(to.Indexes, index.Name, indexName); !ok || !r.sameIndex(index, current) {
+			changes = append(changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("drop index %s of table %s", index.Name, to.Name),
+				Statements:  r.dropIndex(to.Schema, qualified, index),
+			})
+		}
+	}
+
+	for _, index := range to.Indexes {
+		if created && index.Primary {
+			continue
+		}
+		if current, ok := findByName(from.Indexes, index.Name, indexName); !ok || !r.sameIndex(index, current) {
+			changes = append(changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("create index %s on table %s", index.Name, to.Name),
+				Statements:  []string{r.createIndex(qualified, index)},
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffForeignKeys drops the foreign keys that are removed or changed and adds the ones that are added or changed.
+func (r *Differ) diffForeignKeys(from, to SnapshotTable) []SchemaChange {
+	qualified := r.qualifiedName(to)
+	name := r.grammar.wrap.Table(qualified)
+	foreignKeyName := func(foreignKey ForeignKey) string { return foreignKey.Name }
+
+	var changes []SchemaChange
+	for _, foreignKey := range from.ForeignKeys {
+		if current, ok := findByName(to.ForeignKeys, foreignKey.Name, foreignKeyName); !ok || !r.sameForeignKey(foreignKey, current) {
+			changes = append(changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("drop foreign key %s of table %s", foreignKey.Name, to.Name),
+				Statements:  []string{r.dropConstraint(name, foreignKey.Name)},
+			})
+		}
+	}
+	for _, foreignKey := range to.ForeignKeys {
+		if current, ok := findByName(from.ForeignKeys, foreignKey.Name, foreignKeyName); !ok || !r.sameForeignKey(foreignKey, current) {
+			changes = append(changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("add foreign key %s to table %s", foreignKey.Name, to.Name),
+				Statements: []string{r.grammar.CompileAddForeignKey(contracts.ForeignKey{
+					Table:              qualified,
+					Name:               foreignKey.Name,
+					Columns:            foreignKey.Columns,
+					On:                 foreignKey.ForeignSchema + "." + foreignKey.ForeignTable,
+					References:         foreignKey.ForeignColumns,
+					OnDelete:           foreignKeyAction(foreignKey.OnDelete),
+					OnUpdate:           foreignKeyAction(foreignKey.OnUpdate),
+					Match:              foreignKey.Match,
+					Deferrable:         r.deferrable(foreignKey),
+					InitiallyImmediate: r.initiallyImmediate(foreignKey),
+				})},
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffTriggers drops the triggers that are removed or changed and creates the ones that are added or changed.
+func (r *Differ) diffTriggers(from, to SnapshotTable) []SchemaChange {
+	qualified := r.qualifiedName(to)
+	triggerName := func(trigger Trigger) string { return trigger.Name }
+
+	var changes []SchemaChange
+	for _, trigger := range from.Triggers {
+		if current, ok := findByName(to.Triggers, trigger.Name, triggerName); !ok || !r.sameTrigger(trigger, current) {
+			changes = append(changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("drop trigger %s of table %s", trigger.Name, to.Name),
+				Statements:  []string{r.grammar.CompileDropTrigger(qualified, trigger.Name)},
+			})
+		}
+	}
+	for _, trigger := range to.Triggers {
+		if current, ok := findByName(from.Triggers, trigger.Name, triggerName); !ok || !r.sameTrigger(trigger, current) {
+			changes = append(changes, SchemaChange{
+				Table:       to.Name,
+				Description: fmt.Sprintf("create trigger %s on table %s", trigger.Name, to.Name),
+				Statements:  []string{r.grammar.compileTrigger(r.grammar.wrap.Table(qualified), trigger)},
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffTypes creates the types that are added, the domains after the enums and the ranges they may be based on
+// and the composite types after the domains, and adds the new values of the enums. The values that are removed from an enum can't be dropped,
+// so they are reported as destructive changes without statements.
+func (r *Differ) diffTypes(diff *SchemaDiff, from, to []Type) {
+	var enums, ranges, domains, composites []SchemaChange
+	for _, t := range to {
+		name := t.Schema + "." + t.Name
+		current, ok := r.findType(from, t)
+		if !ok {
+			create := SchemaChange{
+				Description: fmt.Sprintf("create %s %s", t.Type.Type, name),
+				Statements:  []string{r.grammar.compileType(t)},
+			}
+			switch t.Type.Type {
+			case "enum":
+				enums = append(enums, create)
+			case "range":
+				ranges = append(ranges, create)
+			case "domain":
+				domains = append(domains, create)
+			case "composite":
+				composites = append(composites, create)
+			}
+
+			continue
+		}
+
+		if t.Type.Type == "domain" {
+			domains = append(domains, r.diffDomain(diff, current, t)...)
+		}
+		if t.Type.Type != "enum" {
+			continue
+		}
+		for i, label := range t.Labels {
+			if slices.Contains(current.Labels, label) {
+				continue
+			}
+
+			// The value is added before the next value that exists, so the order of the values is kept.
+			sql := fmt.Sprintf("alter type %s add value %s", r.grammar.EscapeNames([]string{name})[0], r.grammar.quoteString(label))
+			for _, next := range t.Labels[i+1:] {
+				if slices.Contains(current.Labels, next) {
+					sql += " before " + r.grammar.quoteString(next)
+					break
+				}
+			}
+			enums = append(enums, SchemaChange{
+				Description: fmt.Sprintf("add value %s to enum %s", label, name),
+				Statements:  []string{sql},
+			})
+		}
+		for _, label := range current.Labels {
+			if !slices.Contains(t.Labels, label) {
+				diff.Destructive = append(diff.Destructive, SchemaChange{
+					Description: fmt.Sprintf("value %s of enum %s is removed, it can't be dropped from the enum", label, name),
+				})
+			}
+		}
+	}
+
+	diff.Changes = slices.Concat(diff.Changes, enums, ranges, domains, composites)
+}
+
+// diffDomain changes the default, the not null constraint and the checks of a domain. The base type and the
+// collation of a domain can't be changed, the domain has to be dropped with the columns that use it, so they're
+// reported as destructive changes without statements.
+func (r *Differ) diffDomain(diff *SchemaDiff, from, to Type) []SchemaChange {
+	name := to.Schema + "." + to.Name
+	if from.BaseType != to.BaseType {
+		diff.Destructive = append(diff.Destructive, SchemaChange{
+			Description: fmt.Sprintf("base type of domain %s is changed from %s to %s, the domain can't be altered", name, from.BaseType, to.BaseType),
+		})
+	}
+	if from.Collation != to.Collation {
+		diff.Destructive = append(diff.Destructive, SchemaChange{
+			Description: fmt.Sprintf("collation of domain %s is changed from %s to %s, the domain can't be altered", name, from.Collation, to.Collation),
+		})
+	}
+
+	var changes []SchemaChange
+	if from.Default != to.Default {
+		change := SchemaChange{
+			Description: fmt.Sprintf("drop the default of domain %s", name),
+			Statements:  []string{fmt.Sprintf("alter domain %s drop default", r.grammar.EscapeNames([]string{name})[0])},
+		}
+		if to.Default != "" {
+			change.Description = fmt.Sprintf("change the default of domain %s", name)
+			change.Statements = []string{fmt.Sprintf("alter domain %s set default %s", r.grammar.EscapeNames([]string{name})[0], to.Default)}
+		}
+		changes = append(changes, change)
+	}
+	if from.NotNull != to.NotNull {
+		change := SchemaChange{
+			Description: fmt.Sprintf("make domain %s nullable", name),
+			Statements:  []string{fmt.Sprintf("alter domain %s drop not null", r.grammar.EscapeNames([]string{name})[0])},
+		}
+		if to.NotNull {
+			change.Description = fmt.Sprintf("make domain %s not null", name)
+			change.Statements = []string{fmt.Sprintf("alter domain %s set not null", r.grammar.EscapeNames([]string{name})[0])}
+		}
+		changes = append(changes, change)
+	}
+
+	checkName := func(check contracts.Check) string { return check.Name }
+	for _, check := range from.Checks {
+		if current, ok := findByName(to.Checks, check.Name, checkName); !ok || current.Expression != check.Expression {
+			changes = append(changes, SchemaChange{
+				Description: fmt.Sprintf("drop check %s of domain %s", check.Name, name),
+				Statements:  []string{r.grammar.CompileDropDomainConstraint(name, check.Name)},
+			})
+		}
+	}
+	for _, check := range to.Checks {
+		if current, ok := findByName(from.Checks, check.Name, checkName); !ok || current.Expression != check.Expression {
+			changes = append(changes, SchemaChange{
+				Description: fmt.Sprintf("add check %s to domain %s", check.Name, name),
+				Statements:  []string{r.grammar.CompileAddDomainConstraint(name, check)},
+			})
+		}
+	}
+
+	return changes
+}
+
+// dropTypes drops the types that are removed in the reverse order of their creation.
+func (r *Differ) dropTypes(diff *SchemaDiff, from, to []Type) {
+	var enums, ranges, domains, composites []SchemaChange
+	for _, t := range from {
+		if _, ok := r.findType(to, t); ok {
+			continue
+		}
+
+		name := t.Schema + "." + t.Name
+		switch t.Type.Type {
+		case "enum":
+			enums = append(enums, SchemaChange{
+				Description: fmt.Sprintf("drop enum %s", name),
+				Statements:  []string{r.grammar.CompileDropType(name)},
+			})
+		case "range":
+			ranges = append(ranges, SchemaChange{
+				Description: fmt.Sprintf("drop range %s", name),
+				Statements:  []string{r.grammar.CompileDropType(name)},
+			})
+		case "domain":
+			domains = append(domains, SchemaChange{
+				Description: fmt.Sprintf("drop domain %s", name),
+				Statements:  []string{r.grammar.CompileDropDomain(name)},
+			})
+		case "composite":
+			composites = append(composites, SchemaChange{
+				Description: fmt.Sprintf("drop composite %s", name),
+				Statements:  []string{r.grammar.CompileDropType(name)},
+			})
+		}
+	}
+
+	diff.Destructive = slices.Concat(diff.Destructive, composites, domains, ranges, enums)
+}
+
+func (r *Differ) commentOnColumn(table string, column Column) string {
+	comment := "null"
+	if column.Comment != "" {
+		comment = r.grammar.quoteString(column.Comment)
+	}
+
+	return fmt.Sprintf("comment on column %s.%s is %s", table, r.grammar.wrap.Column(column.Name), comment)
+}
+
+// createIndex uses the definition of the index, the other properties can't recreate e.g. its operator
+// classes or its predicate, they are only used when the snapshot has no definition. The index of a unique
+// or exclusion constraint is created by adding the constraint.
+func (r *Differ) createIndex(table string, index Index) string {
+	if index.Primary {
+		return fmt.Sprintf("alter table %s add constraint %s primary key (%s)", r.grammar.wrap.Table(table), r.grammar.wrap.Value(index.Name), r.grammar.wrap.Columnize(index.Columns))
+	}
+	if index.ConstraintDefinition != "" {
+		return fmt.Sprintf("alter table %s add constraint %s %s", r.grammar.wrap.Table(table), r.grammar.wrap.Value(index.Name), index.ConstraintDefinition)
+	}
+	if index.Definition != "" {
+		return index.Definition
+	}
+
+	return r.grammar.CompileCreateIndex(contracts.Index{
+		Table:     table,
+		Name:      index.Name,
+		Columns:   index.Columns,
+		Algorithm: index.Type,
+		Unique:    index.Unique,
+	})
+}
+
+func (r *Differ) dropConstraint(table, name string) string {
+	return fmt.Sprintf("alter table %s drop constraint %s", table, r.grammar.wrap.Value(name))
+}
+
+// dropIndex drops an index via its constraint when it has one, the index of a constraint can't be dropped
+// directly, and via drop index otherwise. A unique index of a snapshot without definitions may have one.
+func (r *Differ) dropIndex(schema, table string, index Index) []string {
+	if index.Primary || index.ConstraintDefinition != "" {
+		return []string{r.dropConstraint(r.grammar.wrap.Table(table), index.Name)}
+	}
+
+	sql := fmt.Sprintf("drop index %s", r.grammar.EscapeNames([]string{schema + "." + index.Name})[0])
+	if index.Unique && index.Definition == "" {
+		return []string{
+			fmt.Sprintf("alter table %s drop constraint if exists %s", r.grammar.wrap.Table(table), r.grammar.wrap.Value(index.Name)),
+			strings.Replace(sql, "drop index", "drop index if exists", 1),
+		}
+	}
+
+	return []string{sql}
+}
+
+func (r *Differ) findTable(tables []SnapshotTable, table SnapshotTable) (SnapshotTable, bool) {
+	index := slices.IndexFunc(tables, func(t SnapshotTable) bool {
+		return t.Schema == table.Schema && t.Name == table.Name
+	})
+	if index < 0 {
+		return SnapshotTable{}, false
+	}
+
+	return tables[index], true
+}
+
+func (r *Differ) findType(types []Type, t Type) (Type, bool) {
+	index := slices.IndexFunc(types, func(current Type) bool {
+		return current.Schema == t.Schema && current.Name == t.Name
+	})
+	if index < 0 {
+		return Type{}, false
+	}
+
+	return types[index], true
+}
+
+func (r *Differ) qualifiedName(table SnapshotTable) string {
+	return table.Schema + "." + table.Name
+}
+
+// sameIndex compares the definitions of the indexes when both snapshots have them, they contain the name of
+// the schema and of the table, which are the same for the indexes of a table of the snapshots.
+func (r *Differ) sameIndex(a, b Index) bool {
+	if a.Definition != "" && b.Definition != "" && (a.Definition != b.Definition || a.ConstraintDefinition != b.ConstraintDefinition) {
+		return false
+	}
+
+	return slices.Equal(a.Columns, b.Columns) && a.Type == b.Type && a.Unique == b.Unique && a.Primary == b.Primary
+}
+
+func (r *Differ) sameForeignKey(a, b ForeignKey) bool {
+	return slices.Equal(a.Columns, b.Columns) &&
+		a.ForeignSchema == b.ForeignSchema &&
+		a.ForeignTable == b.ForeignTable &&
+		slices.Equal(a.ForeignColumns, b.ForeignColumns) &&
+		foreignKeyAction(a.OnDelete) == foreignKeyAction(b.OnDelete) &&
+		foreignKeyAction(a.OnUpdate) == foreignKeyAction(b.OnUpdate) &&
+		a.Match == b.Match &&
+		a.Deferrable == b.Deferrable &&
+		a.InitiallyDeferred == b.InitiallyDeferred
+}
+
+func (r *Differ) sameTrigger(a, b Trigger) bool {
+	if a.Definition != "" && b.Definition != "" {
+		return a.Definition == b.Definition
+	}
+
+	return a.Timing == b.Timing &&
+		slices.Equal(a.Events, b.Events) &&
+		a.ForEach == b.ForEach &&
+		a.When == b.When &&
+		a.Statement == b.Statement
+}
+
+// deferrable returns nil for the foreign keys that aren't deferrable, which is the default.
+func (r *Differ) deferrable(foreignKey ForeignKey) *bool {
+	if !foreignKey.Deferrable {
+		return nil
+	}
+
+	return &foreignKey.Deferrable
+}
+
+func (r *Differ) initiallyImmediate(foreignKey ForeignKey) *bool {
+	if !foreignKey.Deferrable {
+		return nil
+	}
+
+	initiallyImmediate := !foreignKey.InitiallyDeferred
+	return &initiallyImmediate
+}
+
+func findByName[T any](items []T, name string, getName func(T) string) (T, bool) {
+	for _, item := range items {
+		if getName(item) == name {
+			return item, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// foreignKeyAction returns an empty action for no action, which is the default one.
+func foreignKeyAction(action string) string {
+	if action == "no action" {
+		return ""
+	}
+
+	return action
+}
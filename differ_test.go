@@ -0,0 +1,379 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goravel/framework/contracts/database/driver"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goravel/postgres/contracts"
+)
+
+type DifferTestSuite struct {
+	suite.Suite
+	differ *Differ
+}
+
+func TestDifferTestSuite(t *testing.T) {
+	suite.Run(t, new(DifferTestSuite))
+}
+
+func (s *DifferTestSuite) SetupTest() {
+	s.differ = NewDiffer(NewGrammar("goravel_"))
+}
+
+func (s *DifferTestSuite) TestDiff() {
+	from := &Snapshot{
+		Tables: []SnapshotTable{
+			{
+				Schema: "public",
+				Name:   "users",
+				Columns: []Column{
+					{Column: driver.Column{Name: "id", Type: "bigint", Default: "nextval('goravel_users_id_seq'::regclass)"}},
+					{Column: driver.Column{Name: "name", Type: "character varying(100)", Nullable: true}},
+					{Column: driver.Column{Name: "age", Type: "integer", Nullable: true, Default: "0"}},
+					{Column: driver.Column{Name: "legacy", Type: "text", Nullable: true}},
+				},
+				Checks: []contracts.Check{{Name: "goravel_users_age_check", Expression: "age > 0"}},
+				Indexes: []Index{
+					{Index: driver.Index{Name: "goravel_users_pkey", Columns: []string{"id"}, Type: "btree", Primary: true, Unique: true}},
+					{Index: driver.Index{Name: "goravel_users_name_index", Columns: []string{"name"}, Type: "btree"}, Definition: "CREATE INDEX goravel_users_name_index ON public.goravel_users USING btree (name)"},
+				},
+			},
+			{Schema: "public", Name: "logs", Columns: []Column{{Column: driver.Column{Name: "id", Type: "bigint"}}}},
+		},
+		Types: []Type{
+			{Type: driver.Type{Name: "status", Schema: "public", Type: "enum"}, Labels: []string{"active", "banned", "deleted"}},
+			{Type: driver.Type{Name: "zip", Schema: "public", Type: "domain"}, BaseType: "text"},
+		},
+	}
+	to := &Snapshot{
+		Tables: []SnapshotTable{
+			{
+				Schema:  "public",
+				Name:    "users",
+				Comment: "The users",
+				Columns: []Column{
+					{Column: driver.Column{Name: "id", Type: "bigint", Default: "nextval('goravel_users_id_seq'::regclass)"}},
+					{Column: driver.Column{Name: "name", Type: "character varying(255)"}},
+					{Column: driver.Column{Name: "age", Type: "integer", Nullable: true, Comment: "In years"}},
+					{Column: driver.Column{Name: "status", Type: "status", Nullable: true}},
+					{Column: driver.Column{Name: "team_id", Type: "bigint", Nullable: true}},
+				},
+				Checks: []contracts.Check{{Name: "goravel_users_age_check", Expression: "age >= 0"}},
+				Indexes: []Index{
+					{Index: driver.Index{Name: "goravel_users_pkey", Columns: []string{"id"}, Type: "btree", Primary: true, Unique: true}},
+					{Index: driver.Index{Name: "goravel_users_name_index", Columns: []string{"name"}, Type: "btree"}, Definition: "CREATE INDEX goravel_users_name_index ON public.goravel_users USING hash (name)"},
+					{Index: driver.Index{Name: "goravel_users_status_index", Columns: []string{"status"}, Type: "btree"}},
+				},
+				ForeignKeys: []ForeignKey{
+					{ForeignKey: driver.ForeignKey{Name: "goravel_users_team_id_foreign", Columns: []string{"team_id"}, ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: []string{"id"}, OnUpdate: "no action", OnDelete: "cascade"}},
+				},
+			},
+			{
+				Schema: "public",
+				Name:   "teams",
+				Columns: []Column{
+					{Column: driver.Column{Name: "id", Type: "bigint"}},
+					{Column: driver.Column{Name: "name", Type: "zip", Comment: "It's the name"}},
+				},
+				Indexes: []Index{
+					{Index: driver.Index{Name: "goravel_teams_pkey", Columns: []string{"id"}, Type: "btree", Primary: true, Unique: true}},
+					{Index: driver.Index{Name: "goravel_teams_name_unique", Columns: []string{"name"}, Type: "btree", Unique: true}},
+				},
+			},
+		},
+		Types: []Type{
+			{Type: driver.Type{Name: "status", Schema: "public", Type: "enum"}, Labels: []string{"pending", "active", "deleted", "archived"}},
+			{Type: driver.Type{Name: "mood", Schema: "public", Type: "enum"}, Labels: []string{"happy"}},
+			{Type: driver.Type{Name: "zip", Schema: "public", Type: "domain"}, BaseType: "text"},
+		},
+	}
+
+	diff := s.differ.Diff(from, to)
+	s.False(diff.Empty())
+	s.Equal([]string{
+		`alter type "public"."status" add value 'pending' before 'active'`,
+		`alter type "public"."status" add value 'archived'`,
+		`create type "public"."mood" as enum ('happy')`,
+		`alter table "public"."goravel_users" alter column "name" type character varying(255)`,
+		`alter table "public"."goravel_users" add constraint "goravel_users_name_not_null" check ("name" is not null) not valid`,
+		`alter table "public"."goravel_users" validate constraint "goravel_users_name_not_null"`,
+		`alter table "public"."goravel_users" alter column "name" set not null`,
+		`alter table "public"."goravel_users" drop constraint "goravel_users_name_not_null"`,
+		`alter table "public"."goravel_users" alter column "age" drop default`,
+		`comment on column "public"."goravel_users"."age" is 'In years'`,
+		`alter table "public"."goravel_users" add column "status" status`,
+		`alter table "public"."goravel_users" add column "team_id" bigint`,
+		`comment on table "public"."goravel_users" is 'The users'`,
+		`alter table "public"."goravel_users" drop constraint "goravel_users_age_check"`,
+		`alter table "public"."goravel_users" add constraint "goravel_users_age_check" check (age >= 0)`,
+		`create table "public"."goravel_teams" ("id" bigint not null, "name" zip not null, constraint "goravel_teams_pkey" primary key ("id"))`,
+		`comment on column "public"."goravel_teams"."name" is 'It''s the name'`,
+		`drop index "public"."goravel_users_name_index"`,
+		`CREATE INDEX goravel_users_name_index ON public.goravel_users USING hash (name)`,
+		`create index "goravel_users_status_index" on "public"."goravel_users" using btree ("status")`,
+		`create unique index "goravel_teams_name_unique" on "public"."goravel_teams" using btree ("name")`,
+		`alter table "public"."goravel_users" add constraint "goravel_users_team_id_foreign" foreign key ("team_id") references "public"."goravel_teams" ("id") on delete cascade`,
+	}, diff.Statements(false))
+
+	s.Equal([]SchemaChange{
+		{Description: "value banned of enum public.status is removed, it can't be dropped from the enum"},
+		{Table: "users", Description: "drop column users.legacy", Statements: []string{`alter table "public"."goravel_users" drop column "legacy"`}},
+		{Table: "logs", Description: "drop table logs", Statements: []string{`drop table "public"."goravel_logs"`}},
+	}, diff.Destructive)
+	s.Equal("change the type of column users.name from character varying(100) to character varying(255)", diff.Changes[3].Description)
+}
+
+func (s *DifferTestSuite) TestDiffDropsChangedIndexesAndForeignKeys() {
+	from := &Snapshot{ServerVersion: 170002, Tables: []SnapshotTable{{
+		Schema: "public",
+		Name:   "users",
+		Columns: []Column{
+			{Column: driver.Column{Name: "email", Type: "text"}},
+			{Column: driver.Column{Name: "team_id", Type: "bigint"}},
+			{Column: driver.Column{Name: "number", Type: "integer"}, Identity: "always"},
+			{Column: driver.Column{Name: "total", Type: "integer"}, Generated: "stored", Generation: "(number * 2)"},
+		},
+		Indexes: []Index{
+			{Index: driver.Index{Name: "goravel_users_pkey", Columns: []string{"email"}, Type: "btree", Primary: true, Unique: true}},
+			{Index: driver.Index{Name: "goravel_users_email_unique", Columns: []string{"email"}, Type: "btree", Unique: true}},
+		},
+		ForeignKeys: []ForeignKey{
+			{ForeignKey: driver.ForeignKey{Name: "goravel_users_team_id_foreign", Columns: []string{"team_id"}, ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: []string{"id"}, OnDelete: "no action"}},
+		},
+	}}}
+	to := &Snapshot{Tables: []SnapshotTable{{
+		Schema: "public",
+		Name:   "users",
+		Columns: []Column{
+			{Column: driver.Column{Name: "email", Type: "text"}},
+			{Column: driver.Column{Name: "team_id", Type: "bigint"}, Identity: "by default"},
+			{Column: driver.Column{Name: "number", Type: "integer"}},
+			{Column: driver.Column{Name: "total", Type: "integer"}, Generated: "stored", Generation: "(number * 3)"},
+		},
+		Indexes: []Index{
+			{Index: driver.Index{Name: "goravel_users_pkey", Columns: []string{"email", "team_id"}, Type: "btree", Primary: true, Unique: true}},
+		},
+		ForeignKeys: []ForeignKey{
+			{ForeignKey: driver.ForeignKey{Name: "goravel_users_team_id_foreign", Columns: []string{"team_id"}, ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: []string{"id"}, OnDelete: "set null"}},
+		},
+	}}}
+
+	s.Equal([]string{
+		`alter table "public"."goravel_users" alter column "team_id" add generated by default as identity`,
+		`alter table "public"."goravel_users" alter column "number" drop identity if exists`,
+		`alter table "public"."goravel_users" alter column "total" set expression as ((number * 3))`,
+		`alter table "public"."goravel_users" drop constraint "goravel_users_pkey"`,
+		`alter table "public"."goravel_users" drop constraint if exists "goravel_users_email_unique"`,
+		`drop index if exists "public"."goravel_users_email_unique"`,
+		`alter table "public"."goravel_users" add constraint "goravel_users_pkey" primary key ("email", "team_id")`,
+		`alter table "public"."goravel_users" drop constraint "goravel_users_team_id_foreign"`,
+		`alter table "public"."goravel_users" add constraint "goravel_users_team_id_foreign" foreign key ("team_id") references "public"."goravel_teams" ("id") on delete set null`,
+	}, s.differ.Diff(from, to).Statements(true))
+	s.True(s.differ.Diff(from, from).Empty())
+
+	// The expression can't be changed before PostgreSQL 17, the column is added again.
+	from.ServerVersion = 160004
+	s.Equal([]SchemaChange{{
+		Table:       "users",
+		Description: "drop column users.total and add it again with the new generation expression, changing it requires PostgreSQL 17",
+		Statements: []string{
+			`alter table "public"."goravel_users" drop column "total"`,
+			`alter table "public"."goravel_users" add column "total" integer generated always as ((number * 3)) stored not null`,
+		},
+	}}, s.differ.Diff(from, to).Destructive)
+}
+
+func (s *DifferTestSuite) TestDiffColumnCollations() {
+	from := &Snapshot{Tables: []SnapshotTable{{
+		Schema: "public",
+		Name:   "users",
+		Columns: []Column{
+			{Column: driver.Column{Name: "name", Type: "text", Collation: "C"}},
+			{Column: driver.Column{Name: "email", Type: "text"}},
+			{Column: driver.Column{Name: "bio", Type: "character varying(100)"}},
+		},
+	}}}
+	to := &Snapshot{Tables: []SnapshotTable{{
+		Schema: "public",
+		Name:   "users",
+		Columns: []Column{
+			{Column: driver.Column{Name: "name", Type: "text"}},
+			{Column: driver.Column{Name: "email", Type: "text", Collation: "C"}},
+			{Column: driver.Column{Name: "bio", Type: "text", Collation: "C"}},
+		},
+	}}}
+
+	diff := s.differ.Diff(from, to)
+	s.Equal([]string{
+		`alter table "public"."goravel_users" alter column "name" type text collate "default"`,
+		`alter table "public"."goravel_users" alter column "email" type text collate "C"`,
+		`alter table "public"."goravel_users" alter column "bio" type text collate "C"`,
+	}, diff.Statements(false))
+	s.Equal("change the collation of column users.name from C to default", diff.Changes[0].Description)
+	s.Equal("change the type of column users.bio from character varying(100) to text", diff.Changes[2].Description)
+	s.True(s.differ.Diff(to, to).Empty())
+}
+
+func (s *DifferTestSuite) TestDiffConstraintsAndTriggers() {
+	from := &Snapshot{Tables: []SnapshotTable{{
+		Schema: "public",
+		Name:   "users",
+		Columns: []Column{
+			{Column: driver.Column{Name: "number", Type: "integer"}},
+			{Column: driver.Column{Name: "total", Type: "integer"}},
+		},
+		Indexes: []Index{
+			{Index: driver.Index{Name: "goravel_users_number_unique", Columns: []string{"number"}, Type: "btree", Unique: true}, Definition: "CREATE UNIQUE INDEX goravel_users_number_unique ON public.goravel_users USING btree (number)", ConstraintDefinition: "UNIQUE (number)"},
+		},
+		ForeignKeys: []ForeignKey{
+			{ForeignKey: driver.ForeignKey{Name: "goravel_users_team_id_foreign", Columns: []string{"team_id"}, ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: []string{"id"}}},
+		},
+		Triggers: []Trigger{
+			{Name: "users_touch", Timing: "BEFORE", Events: []string{"UPDATE"}, ForEach: "ROW", Statement: "EXECUTE FUNCTION touch()"},
+			{Name: "users_audit", Timing: "AFTER", Events: []string{"INSERT"}, ForEach: "ROW", Statement: "EXECUTE FUNCTION audit()"},
+		},
+	}}}
+	to := &Snapshot{Tables: []SnapshotTable{{
+		Schema: "public",
+		Name:   "users",
+		Columns: []Column{
+			{Column: driver.Column{Name: "number", Type: "integer"}},
+			{Column: driver.Column{Name: "total", Type: "integer", Comment: "The total"}, Generated: "stored", Generation: "(number * 2)"},
+		},
+		Indexes: []Index{
+			{Index: driver.Index{Name: "goravel_users_number_unique", Columns: []string{"number"}, Type: "btree", Unique: true}, Definition: "CREATE UNIQUE INDEX goravel_users_number_unique ON public.goravel_users USING btree (number)", ConstraintDefinition: "UNIQUE (number) DEFERRABLE"},
+			{Index: driver.Index{Name: "goravel_users_total_index", Columns: []string{"total"}, Type: "btree"}, Definition: "CREATE INDEX goravel_users_total_index ON public.goravel_users USING btree (total)"},
+		},
+		ForeignKeys: []ForeignKey{
+			{ForeignKey: driver.ForeignKey{Name: "goravel_users_team_id_foreign", Columns: []string{"team_id"}, ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: []string{"id"}}, Match: "full", Deferrable: true, InitiallyDeferred: true},
+		},
+		Triggers: []Trigger{
+			{Name: "users_touch", Timing: "BEFORE", Events: []string{"UPDATE"}, ForEach: "ROW", When: "(old.* IS DISTINCT FROM new.*)", Statement: "EXECUTE FUNCTION touch()"},
+			{Name: "users_log", Timing: "AFTER", Events: []string{"DELETE"}, ForEach: "STATEMENT", Statement: "EXECUTE FUNCTION log()"},
+		},
+	}}}
+
+	diff := s.differ.Diff(from, to)
+	s.Equal([]string{
+		`alter table "public"."goravel_users" drop constraint "goravel_users_number_unique"`,
+		`alter table "public"."goravel_users" add constraint "goravel_users_number_unique" UNIQUE (number) DEFERRABLE`,
+		`CREATE INDEX goravel_users_total_index ON public.goravel_users USING btree (total)`,
+		`alter table "public"."goravel_users" drop constraint "goravel_users_team_id_foreign"`,
+		`alter table "public"."goravel_users" add constraint "goravel_users_team_id_foreign" foreign key ("team_id") references "public"."goravel_teams" ("id") match full deferrable initially deferred`,
+		`drop trigger if exists "users_touch" on "public"."goravel_users"`,
+		`drop trigger if exists "users_audit" on "public"."goravel_users"`,
+		`create trigger "users_touch" BEFORE UPDATE on "public"."goravel_users" for each ROW when ((old.* IS DISTINCT FROM new.*)) EXECUTE FUNCTION touch()`,
+		`create trigger "users_log" AFTER DELETE on "public"."goravel_users" for each STATEMENT EXECUTE FUNCTION log()`,
+	}, diff.Statements(false))
+	s.Equal([]SchemaChange{{
+		Table:       "users",
+		Description: "drop column users.total and add it again as a generated column",
+		Statements: []string{
+			`alter table "public"."goravel_users" drop column "total"`,
+			`alter table "public"."goravel_users" add column "total" integer generated always as ((number * 2)) stored not null`,
+			`comment on column "public"."goravel_users"."total" is 'The total'`,
+		},
+	}}, diff.Destructive)
+	s.True(s.differ.Diff(to, to).Empty())
+}
+
+func (s *DifferTestSuite) TestDiffDropsForeignKeysBeforeTables() {
+	from := &Snapshot{Tables: []SnapshotTable{
+		{
+			Schema: "public",
+			Name:   "users",
+			ForeignKeys: []ForeignKey{
+				{ForeignKey: driver.ForeignKey{Name: "goravel_users_team_id_foreign", Columns: []string{"team_id"}, ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: []string{"id"}}},
+			},
+		},
+		{
+			Schema: "public",
+			Name:   "teams",
+			ForeignKeys: []ForeignKey{
+				{ForeignKey: driver.ForeignKey{Name: "goravel_teams_owner_id_foreign", Columns: []string{"owner_id"}, ForeignSchema: "public", ForeignTable: "users", ForeignColumns: []string{"id"}}},
+			},
+		},
+	}}
+
+	s.Equal([]string{
+		`alter table "public"."goravel_users" drop constraint "goravel_users_team_id_foreign"`,
+		`alter table "public"."goravel_teams" drop constraint "goravel_teams_owner_id_foreign"`,
+		`drop table "public"."goravel_users"`,
+		`drop table "public"."goravel_teams"`,
+	}, s.differ.Diff(from, &Snapshot{}).Statements(true))
+}
+
+func (s *DifferTestSuite) TestDiffDomains() {
+	from := &Snapshot{Types: []Type{{
+		Type:     driver.Type{Name: "email", Schema: "public", Type: "domain"},
+		BaseType: "text",
+		Default:  "'none'::text",
+		Checks: []contracts.Check{
+			{Name: "email_check", Expression: "VALUE ~ '@'"},
+			{Name: "email_length_check", Expression: "length(VALUE) < 255"},
+		},
+	}}}
+	to := &Snapshot{Types: []Type{{
+		Type:      driver.Type{Name: "email", Schema: "public", Type: "domain"},
+		BaseType:  "character varying(255)",
+		Collation: "C",
+		Default:   "'unknown'::text",
+		NotNull:   true,
+		Checks: []contracts.Check{
+			{Name: "email_check", Expression: "VALUE ~ '^.+@.+$'"},
+		},
+	}}}
+
+	diff := s.differ.Diff(from, to)
+	s.Equal([]string{
+		`alter domain "public"."email" set default 'unknown'::text`,
+		`alter domain "public"."email" set not null`,
+		`alter domain "public"."email" drop constraint if exists "email_check"`,
+		`alter domain "public"."email" drop constraint if exists "email_length_check"`,
+		`alter domain "public"."email" add constraint "email_check" check (VALUE ~ '^.+@.+$')`,
+	}, diff.Statements(false))
+	s.Equal([]SchemaChange{
+		{Description: "base type of domain public.email is changed from text to character varying(255), the domain can't be altered"},
+		{Description: "collation of domain public.email is changed from  to C, the domain can't be altered"},
+	}, diff.Destructive)
+
+	to.Types[0].Default = ""
+	s.Contains(s.differ.Diff(from, to).Statements(false), `alter domain "public"."email" drop default`)
+}
+
+func (s *DifferTestSuite) TestDiffDropsTypes() {
+	from := &Snapshot{Types: []Type{
+		{Type: driver.Type{Name: "status", Schema: "public", Type: "enum"}, Labels: []string{"active"}},
+		{Type: driver.Type{Name: "email", Schema: "public", Type: "domain"}, BaseType: "text"},
+	}}
+
+	diff := s.differ.Diff(from, &Snapshot{})
+	s.Empty(diff.Changes)
+	s.Equal([]string{
+		`drop domain if exists "public"."email"`,
+		`drop type if exists "public"."status"`,
+	}, diff.Statements(true))
+}
+
+func (s *DifferTestSuite) TestReadSnapshot() {
+	path := filepath.Join(s.T().TempDir(), "snapshot.json")
+	s.NoError(os.WriteFile(path, []byte(`{"tables":[{"schema":"public","name":"users","columns":[{"Name":"id","Type":"bigint","ColumnType":"bigInteger"}]}],"types":[{"Name":"status","Type":"enum","Labels":["active"]}]}`), 0644))
+
+	snapshot, err := ReadSnapshot(path)
+	s.NoError(err)
+	s.Equal(&Snapshot{
+		Tables: []SnapshotTable{{
+			Schema:  "public",
+			Name:    "users",
+			Columns: []Column{{Column: driver.Column{Name: "id", Type: "bigint"}, ColumnType: "bigInteger"}},
+		}},
+		Types: []Type{{Type: driver.Type{Name: "status", Type: "enum"}, Labels: []string{"active"}}},
+	}, snapshot)
+
+	_, err = ReadSnapshot(filepath.Join(s.T().TempDir(), "missing.json"))
+	s.Error(err)
+}
@@ -0,0 +1,640 @@
+package postgres
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/driver"
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/support/collect"
+
+	"github.com/goravel/postgres/contracts"
+)
+
+// SchemaDumpPath is the default path of the schema dump, relative to the root of the application.
+const SchemaDumpPath = "schema/postgres-schema.sql"
+
+// The stages of the dump that create the functions.
+const (
+	functionBeforeTables = iota
+	functionAfterTables
+	functionAfterViews
+)
+
+// dumpedMigration is a row of the migrations table.
+type dumpedMigration struct {
+	ID        uint64
+	Migration string
+	Batch     int
+}
+
+// Dumper dumps the schema of a database to SQL via the introspection queries of the grammar, so the migrations
+// that are already run can be squashed into a file that a fresh database loads before running the newer ones.
+// The dump is deterministic, it contains the schemas, extensions, enums, ranges, domains, composite types,
+// sequences, functions and procedures, the tables of the connection with their checks, indexes, foreign keys,
+// comments and triggers, the views with the indexes of the materialized views and the rows of the migrations
+// table. Policies, grants and the rows of the other tables are skipped, and the dump fails on the types that
+// can't be recreated, e.g. the base types.
+type Dumper struct {
+	grammar    *Grammar
+	processor  *Processor
+	query      orm.Query
+	migrations string
+}
+
+func NewDumper(grammar *Grammar, query orm.Query, migrations string) *Dumper {
+	return &Dumper{
+		grammar:    grammar,
+		processor:  NewProcessor(),
+		query:      query,
+		migrations: migrations,
+	}
+}
+
+// Dump returns the SQL statements that recreate the schema.
+func (r *Dumper) Dump() (string, error) {
+	tables, err := r.tables()
+	if err != nil {
+		return "", err
+	}
+
+	sequences, err := r.sequences(tables)
+	if err != nil {
+		return "", err
+	}
+
+	functions, err := r.dumpFunctions()
+	if err != nil {
+		return "", err
+	}
+
+	var statements []string
+	for _, dump := range []func() ([]string, error){
+		r.dumpSchemas,
+		r.dumpExtensions,
+		r.dumpTypes,
+		func() ([]string, error) { return functions[functionBeforeTables], nil },
+		func() ([]string, error) { return r.dumpSequences(sequences), nil },
+		func() ([]string, error) { return r.dumpTables(tables, functions[functionAfterTables]) },
+		func() ([]string, error) { return r.dumpSequenceOwners(sequences), nil },
+		func() ([]string, error) { return r.dumpViews(functions[functionAfterViews]) },
+		func() ([]string, error) { return r.dumpMigrations(tables) },
+	} {
+		dumped, err := dump()
+		if err != nil {
+			return "", err
+		}
+		statements = append(statements, dumped...)
+	}
+
+	// The bodies of the functions may use the tables that are created after them, the setting is reset at the end,
+	// the dump is loaded via a connection of the pool.
+	return "set check_function_bodies = false;\n\n" + strings.Join(statements, ";\n\n") + ";\n\nreset check_function_bodies;\n", nil
+}
+
+// Snapshot returns the state of the tables of the connection and of the types, which Differ compares.
+func (r *Dumper) Snapshot() (*Snapshot, error) {
+	tables, err := r.tables()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := r.query.Raw(r.grammar.CompileServerVersionNum()).Scan(&snapshot.ServerVersion); err != nil {
+		return nil, err
+	}
+
+	for _, t := range tables {
+		table, err := r.table(t)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Tables = append(snapshot.Tables, table)
+	}
+
+	if snapshot.Types, err = r.types(); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// SnapshotToFile writes the snapshot to a JSON file, which ReadSnapshot reads, creating its directory if needed.
+func (r *Dumper) SnapshotToFile(path string) error {
+	snapshot, err := r.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// DumpToFile writes the dump to a file, e.g. SchemaDumpPath, creating its directory if needed.
+func (r *Dumper) DumpToFile(path string) error {
+	sql, err := r.Dump()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(sql), 0644)
+}
+
+// Load runs the statements of a dump file, it's meant for a fresh database before the migrations that are newer than the dump run.
+func (r *Dumper) Load(path string) error {
+	sql, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.query.Exec(string(sql))
+
+	return err
+}
+
+func (r *Dumper) dumpExtensions() ([]string, error) {
+	var extensions []contracts.Extension
+	if err := r.query.Raw(r.grammar.CompileExtensions()).Scan(&extensions); err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, extension := range extensions {
+		// plpgsql is installed in every database.
+		if extension.Name == "plpgsql" {
+			continue
+		}
+
+		statements = append(statements, r.grammar.CompileCreateExtension(contracts.Extension{
+			Name:   extension.Name,
+			Schema: extension.Schema,
+		}))
+	}
+
+	return statements, nil
+}
+
+// dumpFunctions returns the functions and the procedures by the stage of the dump that creates them, the ones
+// that use a table or a view are created after it, and the ones that are used by another one before it.
+func (r *Dumper) dumpFunctions() ([3][]string, error) {
+	var stages [3][]string
+	var functions []Function
+	if err := r.query.Raw(r.grammar.CompileFunctions()).Scan(&functions); err != nil {
+		return stages, err
+	}
+
+	slices.SortFunc(functions, func(a, b Function) int {
+		return cmp.Or(cmp.Compare(a.Schema, b.Schema), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Arguments, b.Arguments))
+	})
+
+	signatures := make(map[string]Function)
+	dependencies := make(map[string][]string)
+	for _, function := range functions {
+		signatures[function.Signature] = function
+		if function.Dependencies != "" {
+			var signatureDependencies []string
+			if err := json.Unmarshal([]byte(function.Dependencies), &signatureDependencies); err != nil {
+				return stages, err
+			}
+			dependencies[function.Signature] = signatureDependencies
+		}
+	}
+
+	stage := make(map[string]int)
+	visited := make(map[string]bool)
+	var visit func(function Function) int
+	visit = func(function Function) int {
+		if visited[function.Signature] {
+			return stage[function.Signature]
+		}
+		visited[function.Signature] = true
+
+		current := functionBeforeTables
+		if function.UsesTables {
+			current = functionAfterTables
+		}
+		if function.UsesViews {
+			current = functionAfterViews
+		}
+		for _, dependency := range dependencies[function.Signature] {
+			if other, ok := signatures[dependency]; ok {
+				current = max(current, visit(other))
+			}
+		}
+		stage[function.Signature] = current
+		stages[current] = append(stages[current], strings.TrimSuffix(strings.TrimSpace(function.Definition), ";"))
+
+		return current
+	}
+	for _, function := range functions {
+		visit(function)
+	}
+
+	return stages, nil
+}
+
+func (r *Dumper) dumpMigrations(tables []driver.Table) ([]string, error) {
+	table, ok := r.findTable(tables, r.grammar.prefix+r.migrations)
+	if !ok {
+		return nil, nil
+	}
+
+	var migrations []dumpedMigration
+	if err := r.query.Raw(fmt.Sprintf("select id, migration, batch from %s order by id", r.qualify(table.Schema, table.Name))).Scan(&migrations); err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, nil
+	}
+
+	values := collect.Map(migrations, func(migration dumpedMigration, _ int) string {
+		return fmt.Sprintf("(%d, %s, %d)", migration.ID, r.grammar.quoteString(migration.Migration), migration.Batch)
+	})
+
+	return []string{
+		fmt.Sprintf("insert into %s (\"id\", \"migration\", \"batch\") values\n%s", r.qualify(table.Schema, table.Name), strings.Join(values, ",\n")),
+		r.grammar.CompileResyncSequences(table.Schema, r.migrations),
+	}, nil
+}
+
+func (r *Dumper) dumpSchemas() ([]string, error) {
+	var schemas []contracts.Schema
+	if err := r.query.Raw(r.grammar.CompileSchemas()).Scan(&schemas); err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, schema := range schemas {
+		// The public schema exists in every database.
+		if schema.Name == "public" {
+			continue
+		}
+
+		statements = append(statements, r.grammar.CompileCreateSchema(contracts.Schema{Name: schema.Name, IfNotExists: true}))
+	}
+
+	return statements, nil
+}
+
+// dumpSequenceOwners ties the sequences to the columns that use them, which requires the tables to exist.
+func (r *Dumper) dumpSequenceOwners(sequences []Sequence) []string {
+	var statements []string
+	for _, sequence := range sequences {
+		if sequence.OwnedBy != "" {
+			statements = append(statements, fmt.Sprintf("alter sequence %s owned by %s",
+				r.qualify(sequence.Schema, sequence.Name), r.grammar.EscapeNames([]string{sequence.Schema + "." + sequence.OwnedBy})[0]))
+		}
+	}
+
+	return statements
+}
+
+func (r *Dumper) dumpSequences(sequences []Sequence) []string {
+	return collect.Map(sequences, func(sequence Sequence, _ int) string {
+		return r.grammar.CompileCreateSequence(contracts.Sequence{
+			Name:      sequence.Schema + "." + sequence.Name,
+			As:        sequence.Type,
+			Increment: &sequence.Increment,
+			MinValue:  &sequence.MinValue,
+			MaxValue:  &sequence.MaxValue,
+			Start:     &sequence.Start,
+			Cache:     &sequence.Cache,
+			Cycle:     &sequence.Cycle,
+		})
+	})
+}
+
+// dumpTables creates all the tables before their indexes, foreign keys and triggers, so the foreign keys
+// can reference the tables that are created after them.
+func (r *Dumper) dumpTables(tables []driver.Table, functions []string) ([]string, error) {
+	var creates, indexes, foreignKeys, triggers []string
+	for _, t := range tables {
+		table, err := r.table(t)
+		if err != nil {
+			return nil, err
+		}
+
+		qualified := r.qualify(table.Schema, r.grammar.prefix+table.Name)
+		definitions := collect.Map(table.Columns, func(column Column, _ int) string {
+			return r.grammar.getColumnDefinition(column)
+		})
+		for _, check := range table.Checks {
+			check.NotValid = false
+			definitions = append(definitions, r.grammar.getCheck(check))
+		}
+		for _, index := range table.Indexes {
+			switch {
+			case index.Primary:
+				definitions = append(definitions, fmt.Sprintf("constraint %s primary key (%s)", r.grammar.wrap.Value(index.Name), r.grammar.wrap.Columnize(index.Columns)))
+			case index.ConstraintDefinition != "":
+				// The unique and exclusion constraints create their indexes, which can't be created separately.
+				indexes = append(indexes, fmt.Sprintf("alter table %s add constraint %s %s", qualified, r.grammar.wrap.Value(index.Name), index.ConstraintDefinition))
+			default:
+				indexes = append(indexes, index.Definition)
+			}
+		}
+
+		creates = append(creates, fmt.Sprintf("create table %s (\n  %s\n)", qualified, strings.Join(definitions, ",\n  ")))
+		if table.Comment != "" {
+			creates = append(creates, fmt.Sprintf("comment on table %s is %s", qualified, r.grammar.quoteString(table.Comment)))
+		}
+		for _, column := range table.Columns {
+			if column.Comment != "" {
+				creates = append(creates, fmt.Sprintf("comment on column %s.%s is %s", qualified, r.grammar.wrap.Column(column.Name), r.grammar.quoteString(column.Comment)))
+			}
+		}
+
+		for _, foreignKey := range table.ForeignKeys {
+			sql := fmt.Sprintf("alter table %s add constraint %s foreign key (%s) references %s (%s)",
+				qualified,
+				r.grammar.wrap.Value(foreignKey.Name),
+				r.grammar.wrap.Columnize(foreignKey.Columns),
+				r.qualify(foreignKey.ForeignSchema, r.grammar.prefix+foreignKey.ForeignTable),
+				r.grammar.wrap.Columnize(foreignKey.ForeignColumns),
+			)
+			if foreignKey.Match != "" {
+				sql += " match " + foreignKey.Match
+			}
+			if foreignKey.OnUpdate != "" && foreignKey.OnUpdate != "no action" {
+				sql += " on update " + foreignKey.OnUpdate
+			}
+			if foreignKey.OnDelete != "" && foreignKey.OnDelete != "no action" {
+				sql += " on delete " + foreignKey.OnDelete
+			}
+			if foreignKey.Deferrable {
+				initiallyImmediate := !foreignKey.InitiallyDeferred
+				sql += r.grammar.getDeferrable(&foreignKey.Deferrable, &initiallyImmediate)
+			}
+			foreignKeys = append(foreignKeys, sql)
+		}
+
+		for _, trigger := range table.Triggers {
+			triggers = append(triggers, r.grammar.compileTrigger(qualified, trigger))
+		}
+	}
+
+	return slices.Concat(creates, functions, indexes, foreignKeys, triggers), nil
+}
+
+func (r *Dumper) dumpTypes() ([]string, error) {
+	types, err := r.types()
+	if err != nil {
+		return nil, err
+	}
+
+	// The enums and the ranges are created before the domains, which may be based on them, and the composite
+	// types after the domains, which their attributes may use.
+	statements := make(map[string][]string)
+	for _, t := range types {
+		sql := r.grammar.compileType(t)
+		if sql == "" {
+			return nil, DumpTypeNotSupported.Args(t.Type.Type, t.Schema+"."+t.Name)
+		}
+		statements[t.Type.Type] = append(statements[t.Type.Type], sql)
+	}
+
+	return slices.Concat(statements["enum"], statements["range"], statements["domain"], statements["composite"]), nil
+}
+
+// dumpViews creates the views after the views they select from, the other views are ordered by their names,
+// and the indexes of the materialized views after the functions that use the views, which the indexes may use.
+func (r *Dumper) dumpViews(functions []string) ([]string, error) {
+	var views []View
+	if err := r.query.Raw(r.grammar.CompileViews("")).Scan(&views); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(views, func(a, b View) int {
+		return cmp.Or(cmp.Compare(a.Schema, b.Schema), cmp.Compare(a.Name, b.Name))
+	})
+
+	views, err := r.sortViews(views)
+	if err != nil {
+		return nil, err
+	}
+
+	var creates, indexes []string
+	for _, view := range views {
+		query := strings.TrimSuffix(strings.TrimSpace(view.Definition), ";")
+		if !view.Materialized {
+			creates = append(creates, fmt.Sprintf("create view %s as\n%s", r.qualify(view.Schema, view.Name), query))
+			continue
+		}
+
+		creates = append(creates, fmt.Sprintf("create materialized view %s as\n%s\nwith no data", r.qualify(view.Schema, view.Name), query))
+		if view.Indexes != "" {
+			var definitions []string
+			if err := json.Unmarshal([]byte(view.Indexes), &definitions); err != nil {
+				return nil, err
+			}
+			indexes = append(indexes, definitions...)
+		}
+	}
+
+	return slices.Concat(creates, functions, indexes), nil
+}
+
+// sortViews sorts the views topologically by their dependencies, keeping the order of the views that don't
+// depend on each other.
+func (r *Dumper) sortViews(views []View) ([]View, error) {
+	key := func(schema, name string) string {
+		return schema + "." + name
+	}
+	dependencies := make(map[string][]string)
+	for _, view := range views {
+		var relations []struct {
+			Schema string
+			Name   string
+		}
+		if view.Dependencies != "" {
+			if err := json.Unmarshal([]byte(view.Dependencies), &relations); err != nil {
+				return nil, err
+			}
+		}
+		for _, relation := range relations {
+			dependencies[key(view.Schema, view.Name)] = append(dependencies[key(view.Schema, view.Name)], key(relation.Schema, relation.Name))
+		}
+	}
+
+	var sorted []View
+	visited := make(map[string]bool)
+	var visit func(view View)
+	visit = func(view View) {
+		if visited[key(view.Schema, view.Name)] {
+			return
+		}
+		visited[key(view.Schema, view.Name)] = true
+		for _, dependency := range dependencies[key(view.Schema, view.Name)] {
+			for _, other := range views {
+				if key(other.Schema, other.Name) == dependency {
+					visit(other)
+				}
+			}
+		}
+		sorted = append(sorted, view)
+	}
+	for _, view := range views {
+		visit(view)
+	}
+
+	return sorted, nil
+}
+
+func (r *Dumper) findTable(tables []driver.Table, name string) (driver.Table, bool) {
+	for _, table := range tables {
+		if table.Name == name {
+			return table, true
+		}
+	}
+
+	return driver.Table{}, false
+}
+
+func (r *Dumper) qualify(schema, name string) string {
+	return r.grammar.EscapeNames([]string{schema + "." + name})[0]
+}
+
+// table introspects a table of the connection, the names of the table and of the tables its foreign keys
+// reference are without the prefix.
+func (r *Dumper) table(t driver.Table) (SnapshotTable, error) {
+	name := strings.TrimPrefix(t.Name, r.grammar.prefix)
+	table := SnapshotTable{Schema: t.Schema, Name: name, Comment: t.Comment}
+
+	sql, err := r.grammar.CompileColumns(t.Schema, name)
+	if err != nil {
+		return table, err
+	}
+	var dbColumns []DBColumn
+	if err := r.query.Raw(sql).Scan(&dbColumns); err != nil {
+		return table, err
+	}
+	table.Columns = r.processor.ProcessColumnDetails(dbColumns)
+
+	if sql, err = r.grammar.CompileChecks(t.Schema, name); err != nil {
+		return table, err
+	}
+	var dbChecks []DBCheck
+	if err := r.query.Raw(sql).Scan(&dbChecks); err != nil {
+		return table, err
+	}
+	table.Checks = r.processor.ProcessChecks(dbChecks)
+
+	if sql, err = r.grammar.CompileIndexes(t.Schema, name); err != nil {
+		return table, err
+	}
+	var dbIndexes []DBIndex
+	if err := r.query.Raw(sql).Scan(&dbIndexes); err != nil {
+		return table, err
+	}
+	table.Indexes = r.processor.ProcessIndexDetails(dbIndexes)
+	slices.SortFunc(table.Indexes, func(a, b Index) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	var dbForeignKeys []DBForeignKey
+	if err := r.query.Raw(r.grammar.CompileForeignKeys(t.Schema, t.Name)).Scan(&dbForeignKeys); err != nil {
+		return table, err
+	}
+	table.ForeignKeys = r.processor.ProcessForeignKeyDetails(dbForeignKeys)
+	for i := range table.ForeignKeys {
+		table.ForeignKeys[i].ForeignTable = strings.TrimPrefix(table.ForeignKeys[i].ForeignTable, r.grammar.prefix)
+	}
+	slices.SortFunc(table.ForeignKeys, func(a, b ForeignKey) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	if sql, err = r.grammar.CompileTriggers(t.Schema, name); err != nil {
+		return table, err
+	}
+	var dbTriggers []DBTrigger
+	if err := r.query.Raw(sql).Scan(&dbTriggers); err != nil {
+		return table, err
+	}
+	table.Triggers = r.processor.ProcessTriggers(dbTriggers)
+
+	return table, nil
+}
+
+// sequences returns the sequences that don't belong to a column or belong to a column of the dumped tables,
+// except the ones of the identity columns.
+func (r *Dumper) sequences(tables []driver.Table) ([]Sequence, error) {
+	var sequences []Sequence
+	if err := r.query.Raw(r.grammar.CompileSequences()).Scan(&sequences); err != nil {
+		return nil, err
+	}
+
+	sequences = slices.DeleteFunc(sequences, func(sequence Sequence) bool {
+		// The sequences of the identity columns are created with the columns.
+		if sequence.Identity {
+			return true
+		}
+		if sequence.OwnedBy == "" {
+			return false
+		}
+
+		table, _, _ := strings.Cut(sequence.OwnedBy, ".")
+		return !slices.ContainsFunc(tables, func(t driver.Table) bool {
+			return t.Schema == sequence.Schema && t.Name == table
+		})
+	})
+	slices.SortFunc(sequences, func(a, b Sequence) int {
+		return cmp.Or(cmp.Compare(a.Schema, b.Schema), cmp.Compare(a.Name, b.Name))
+	})
+
+	return sequences, nil
+}
+
+// types returns the enums, domains, ranges and composite types except the implicit ones, e.g. the arrays.
+func (r *Dumper) types() ([]Type, error) {
+	var dbTypes []DBType
+	if err := r.query.Raw(r.grammar.CompileTypes()).Scan(&dbTypes); err != nil {
+		return nil, err
+	}
+
+	types, err := r.processor.ProcessTypeDetails(dbTypes)
+	if err != nil {
+		return nil, err
+	}
+	types = slices.DeleteFunc(types, func(t Type) bool {
+		return t.Implicit
+	})
+	slices.SortFunc(types, func(a, b Type) int {
+		return cmp.Or(cmp.Compare(a.Schema, b.Schema), cmp.Compare(a.Name, b.Name))
+	})
+
+	return types, nil
+}
+
+// tables returns the tables of the connection, which are the ones with its prefix, except the ones of the extensions.
+func (r *Dumper) tables() ([]driver.Table, error) {
+	var tables []driver.Table
+	if err := r.query.Raw(r.grammar.CompileTables("")).Scan(&tables); err != nil {
+		return nil, err
+	}
+
+	tables = slices.DeleteFunc(tables, func(table driver.Table) bool {
+		return !strings.HasPrefix(table.Name, r.grammar.prefix) || table.Name == "spatial_ref_sys"
+	})
+	slices.SortFunc(tables, func(a, b driver.Table) int {
+		return cmp.Or(cmp.Compare(a.Schema, b.Schema), cmp.Compare(a.Name, b.Name))
+	})
+
+	return tables, nil
+}
@@ -0,0 +1,330 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/goravel/framework/contracts/database/driver"
+	mocksorm "github.com/goravel/framework/mocks/database/orm"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goravel/postgres/contracts"
+)
+
+type DumperTestSuite struct {
+	suite.Suite
+	grammar   *Grammar
+	mockQuery *mocksorm.Query
+	dumper    *Dumper
+}
+
+func TestDumperTestSuite(t *testing.T) {
+	suite.Run(t, new(DumperTestSuite))
+}
+
+func (s *DumperTestSuite) SetupTest() {
+	s.grammar = NewGrammar("goravel_")
+	s.mockQuery = mocksorm.NewQuery(s.T())
+	s.dumper = NewDumper(s.grammar, s.mockQuery, "migrations")
+}
+
+func (s *DumperTestSuite) TestDump() {
+	s.mockDatabase()
+
+	sql, err := s.dumper.Dump()
+	s.NoError(err)
+	s.Equal(`set check_function_bodies = false;
+
+create schema if not exists "archive";
+
+create extension if not exists "vector" schema "public";
+
+create type "public"."status" as enum ('active', 'it''s banned');
+
+create type "public"."floatrange" as range (subtype = double precision, subtype_diff = float8mi, multirange_type_name = "public"."floatmultirange");
+
+create domain "public"."email" as character varying(255) collate "C" default 'nobody@goravel.dev'::character varying not null constraint "email_check" check (((VALUE)::text ~ '^.+@.+$'::text));
+
+create type "public"."address" as ("street" text collate "C", "zip" integer);
+
+CREATE OR REPLACE FUNCTION public.touch()
+ RETURNS trigger
+ LANGUAGE plpgsql
+AS $function$ begin new.updated_at = now(); return new; end $function$;
+
+create sequence "public"."goravel_users_id_seq" as bigint increment by 1 minvalue 1 maxvalue 9223372036854775807 start with 1 cache 1 no cycle;
+
+create table "public"."goravel_migrations" (
+  "id" integer generated by default as identity not null,
+  "migration" character varying(255) not null,
+  "batch" integer not null,
+  constraint "goravel_migrations_pkey" primary key ("id")
+);
+
+create table "public"."goravel_users" (
+  "id" bigint default nextval('goravel_users_id_seq'::regclass) not null,
+  "email" email not null,
+  "bio" text storage external compression lz4 collate "en_US.utf8",
+  "status" status,
+  "manager_id" bigint,
+  "age" integer,
+  "decade" integer generated always as ((age / 10)) stored,
+  constraint "goravel_users_age_check" check ((age > 0)),
+  constraint "goravel_users_pkey" primary key ("id")
+);
+
+comment on table "public"."goravel_users" is 'The users';
+
+comment on column "public"."goravel_users"."email" is 'It''s unique';
+
+CREATE OR REPLACE FUNCTION public.user_label(u goravel_users)
+ RETURNS text
+ LANGUAGE sql
+RETURN u.email;
+
+CREATE INDEX goravel_users_age_index ON public.goravel_users USING btree (age);
+
+alter table "public"."goravel_users" add constraint "goravel_users_email_unique" UNIQUE (email);
+
+alter table "public"."goravel_users" add constraint "goravel_users_manager_id_foreign" foreign key ("manager_id") references "public"."goravel_users" ("id") match full on delete set null deferrable initially deferred;
+
+CREATE TRIGGER users_touch BEFORE UPDATE OF email, status ON public.goravel_users FOR EACH ROW EXECUTE FUNCTION touch();
+
+CREATE TRIGGER users_truncate AFTER TRUNCATE ON public.goravel_users FOR EACH STATEMENT EXECUTE FUNCTION touch();
+
+alter sequence "public"."goravel_users_id_seq" owned by "public"."goravel_users"."id";
+
+create view "public"."active_users" as
+SELECT id, email FROM goravel_users WHERE status = 'active'::status;
+
+create view "public"."active_user_emails" as
+SELECT email FROM active_users;
+
+create materialized view "public"."user_counts" as
+SELECT status, count(*) AS count FROM goravel_users GROUP BY status
+with no data;
+
+CREATE UNIQUE INDEX user_counts_status_unique ON public.user_counts USING btree (status);
+
+insert into "public"."goravel_migrations" ("id", "migration", "batch") values
+(1, '20240101000000_create_users_table', 1),
+(2, '20240102000000_add_status_to_users_table', 2);
+
+`+s.grammar.CompileResyncSequences("public", "migrations")+`;
+
+reset check_function_bodies;
+`, sql)
+}
+
+func (s *DumperTestSuite) TestDumpToFileAndLoad() {
+	s.mockDatabase()
+
+	path := filepath.Join(s.T().TempDir(), SchemaDumpPath)
+	s.NoError(s.dumper.DumpToFile(path))
+
+	content, err := os.ReadFile(path)
+	s.NoError(err)
+	s.Contains(string(content), `create table "public"."goravel_users"`)
+
+	s.mockQuery.EXPECT().Exec(string(content)).Return(nil, nil).Once()
+	s.NoError(s.dumper.Load(path))
+}
+
+func (s *DumperTestSuite) TestSnapshotToFile() {
+	s.mockRaw(s.grammar.CompileTables(""), []driver.Table{
+		{Name: "goravel_users", Schema: "public", Comment: "The users"},
+		{Name: "logs", Schema: "archive"},
+	})
+	s.mockRaw(s.grammar.CompileServerVersionNum(), 170002)
+	s.mockTable("users", []DBColumn{
+		{DBColumn: driver.DBColumn{Name: "id", Type: "bigint", TypeName: "int8"}},
+		{DBColumn: driver.DBColumn{Name: "status", Type: "status", TypeName: "status", Nullable: "true"}},
+	}, nil, []DBIndex{
+		{DBIndex: driver.DBIndex{Name: "goravel_users_pkey", Columns: "id", Type: "btree", Primary: true, Unique: true}},
+	}, []DBForeignKey{
+		{DBForeignKey: driver.DBForeignKey{Name: "goravel_users_manager_id_foreign", Columns: "id", ForeignSchema: "public", ForeignTable: "goravel_users", ForeignColumns: "id", OnUpdate: "a", OnDelete: "c"}, Match: "s"},
+	}, nil)
+	s.mockRaw(s.grammar.CompileTypes(), []DBType{
+		{Type: driver.Type{Name: "status", Schema: "public", Type: "e", Category: "e"}, EnumLabels: `["active", "banned"]`},
+		{Type: driver.Type{Name: "_status", Schema: "public", Type: "b", Category: "a", Implicit: true}},
+	})
+
+	path := filepath.Join(s.T().TempDir(), "schema", "snapshot.json")
+	s.NoError(s.dumper.SnapshotToFile(path))
+
+	snapshot, err := ReadSnapshot(path)
+	s.NoError(err)
+	s.Equal(170002, snapshot.ServerVersion)
+	s.Len(snapshot.Tables, 1)
+	s.Equal("users", snapshot.Tables[0].Name)
+	s.Equal("The users", snapshot.Tables[0].Comment)
+	s.Len(snapshot.Tables[0].Columns, 2)
+	s.Equal("users", snapshot.Tables[0].ForeignKeys[0].ForeignTable)
+	s.Len(snapshot.Types, 1)
+	s.Equal([]string{"active", "banned"}, snapshot.Types[0].Labels)
+	s.True(NewDiffer(s.grammar).Diff(snapshot, snapshot).Empty())
+}
+
+func (s *DumperTestSuite) TestDumpFailsOnInvalidViewDependencies() {
+	s.mockRaw(s.grammar.CompileViews(""), []View{
+		{View: driver.View{Name: "active_users", Schema: "public", Definition: " SELECT id FROM goravel_users;"}, Dependencies: `public.goravel_users`},
+	})
+
+	_, err := s.dumper.dumpViews(nil)
+	s.Error(err)
+}
+
+func (s *DumperTestSuite) TestDumpFunctions() {
+	s.mockRaw(s.grammar.CompileFunctions(), []Function{
+		{Name: "active_names", Schema: "public", Signature: "active_names()", Definition: "CREATE FUNCTION public.active_names() RETURNS SETOF text LANGUAGE sql BEGIN ATOMIC SELECT email FROM active_users; END", UsesViews: true},
+		{Name: "add", Schema: "public", Arguments: "a integer, b integer", Signature: "add(integer,integer)", Definition: "CREATE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql RETURN (a + b)"},
+		{Name: "archive", Schema: "public", Signature: "archive()", Definition: "CREATE PROCEDURE public.archive() LANGUAGE sql BEGIN ATOMIC SELECT add(1, 2); END", Dependencies: `["add(integer,integer)"]`, Procedure: true},
+		{Name: "describe", Schema: "public", Arguments: "id integer", Signature: "describe(integer)", Definition: "CREATE FUNCTION public.describe(id integer) RETURNS text LANGUAGE sql BEGIN ATOMIC SELECT user_name(u.*) FROM goravel_users u; END", Dependencies: `["user_name(goravel_users)"]`},
+		{Name: "user_name", Schema: "public", Arguments: "u goravel_users", Signature: "user_name(goravel_users)", Definition: "CREATE FUNCTION public.user_name(u goravel_users) RETURNS text LANGUAGE sql RETURN u.email;", UsesTables: true},
+	})
+
+	stages, err := s.dumper.dumpFunctions()
+	s.NoError(err)
+	s.Equal([3][]string{
+		{
+			"CREATE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql RETURN (a + b)",
+			"CREATE PROCEDURE public.archive() LANGUAGE sql BEGIN ATOMIC SELECT add(1, 2); END",
+		},
+		{
+			"CREATE FUNCTION public.user_name(u goravel_users) RETURNS text LANGUAGE sql RETURN u.email",
+			"CREATE FUNCTION public.describe(id integer) RETURNS text LANGUAGE sql BEGIN ATOMIC SELECT user_name(u.*) FROM goravel_users u; END",
+		},
+		{
+			"CREATE FUNCTION public.active_names() RETURNS SETOF text LANGUAGE sql BEGIN ATOMIC SELECT email FROM active_users; END",
+		},
+	}, stages)
+}
+
+func (s *DumperTestSuite) TestDumpFailsOnUnsupportedTypes() {
+	s.mockRaw(s.grammar.CompileTypes(), []DBType{
+		{Type: driver.Type{Name: "ltree", Schema: "public", Type: "b", Category: "u"}},
+	})
+
+	_, err := s.dumper.dumpTypes()
+	s.ErrorIs(err, DumpTypeNotSupported)
+}
+
+func (s *DumperTestSuite) mockDatabase() {
+	s.mockRaw(s.grammar.CompileTables(""), []driver.Table{
+		{Name: "goravel_users", Schema: "public", Comment: "The users"},
+		{Name: "spatial_ref_sys", Schema: "public"},
+		{Name: "goravel_migrations", Schema: "public"},
+		{Name: "logs", Schema: "archive"},
+	})
+	s.mockRaw(s.grammar.CompileSequences(), []Sequence{
+		{Name: "goravel_users_id_seq", Schema: "public", Type: "bigint", OwnedBy: "goravel_users.id", Start: 1, Increment: 1, MinValue: 1, MaxValue: 9223372036854775807, Cache: 1},
+		{Name: "logs_id_seq", Schema: "archive", Type: "bigint", OwnedBy: "logs.id", Start: 1, Increment: 1, MinValue: 1, MaxValue: 9223372036854775807, Cache: 1},
+		{Name: "goravel_migrations_id_seq", Schema: "public", Type: "integer", OwnedBy: "goravel_migrations.id", Start: 1, Increment: 1, MinValue: 1, MaxValue: 2147483647, Cache: 1, Identity: true},
+	})
+	s.mockRaw(s.grammar.CompileSchemas(), []contracts.Schema{{Name: "archive"}, {Name: "public"}})
+	s.mockRaw(s.grammar.CompileExtensions(), []contracts.Extension{
+		{Name: "plpgsql", Schema: "pg_catalog", Version: "1.0"},
+		{Name: "vector", Schema: "public", Version: "0.8.0"},
+	})
+	s.mockRaw(s.grammar.CompileTypes(), []DBType{
+		{Type: driver.Type{Name: "status", Schema: "public", Type: "e", Category: "e"}, EnumLabels: `["active", "it's banned"]`},
+		{Type: driver.Type{Name: "_status", Schema: "public", Type: "b", Category: "a", Implicit: true}},
+		{
+			Type:        driver.Type{Name: "email", Schema: "public", Type: "d", Category: "s"},
+			BaseType:    "character varying(255)",
+			Constraints: `[{"name": "email_check", "definition": "CHECK (((VALUE)::text ~ '^.+@.+$'::text))"}]`,
+			Collation:   "C",
+			Default:     "'nobody@goravel.dev'::character varying",
+			NotNull:     true,
+		},
+		{
+			Type:       driver.Type{Name: "address", Schema: "public", Type: "c", Category: "c"},
+			Attributes: `[{"name": "street", "type": "text", "collation": "C"}, {"name": "zip", "type": "integer", "collation": null}]`,
+		},
+		{
+			Type:  driver.Type{Name: "floatrange", Schema: "public", Type: "r", Category: "r"},
+			Range: `{"subtype": "double precision", "subtypeOpClass": null, "collation": null, "canonical": null, "subtypeDiff": "float8mi", "multirangeTypeName": "public.floatmultirange"}`,
+		},
+		{Type: driver.Type{Name: "floatmultirange", Schema: "public", Type: "m", Category: "r", Implicit: true}},
+	})
+	s.mockRaw(s.grammar.CompileFunctions(), []Function{
+		{Name: "touch", Schema: "public", Signature: "touch()", Definition: "CREATE OR REPLACE FUNCTION public.touch()\n RETURNS trigger\n LANGUAGE plpgsql\nAS $function$ begin new.updated_at = now(); return new; end $function$\n"},
+		{Name: "user_label", Schema: "public", Signature: "user_label(goravel_users)", Definition: "CREATE OR REPLACE FUNCTION public.user_label(u goravel_users)\n RETURNS text\n LANGUAGE sql\nRETURN u.email\n", UsesTables: true},
+	})
+
+	s.mockTable("migrations", []DBColumn{
+		{DBColumn: driver.DBColumn{Name: "id", Type: "integer", TypeName: "int4", Autoincrement: true}, Identity: "by default"},
+		{DBColumn: driver.DBColumn{Name: "migration", Type: "character varying(255)", TypeName: "varchar"}},
+		{DBColumn: driver.DBColumn{Name: "batch", Type: "integer", TypeName: "int4"}},
+	}, nil, []DBIndex{
+		{DBIndex: driver.DBIndex{Name: "goravel_migrations_pkey", Columns: "id", Type: "btree", Primary: true, Unique: true}},
+	}, nil, nil)
+	s.mockTable("users", []DBColumn{
+		{DBColumn: driver.DBColumn{Name: "id", Type: "bigint", TypeName: "int8", Default: "nextval('goravel_users_id_seq'::regclass)"}},
+		{DBColumn: driver.DBColumn{Name: "email", Type: "email", TypeName: "email", Comment: "It's unique"}},
+		{DBColumn: driver.DBColumn{Name: "bio", Type: "text", TypeName: "text", Nullable: "true", Collation: "en_US.utf8"}, Compression: "lz4", Storage: "external"},
+		{DBColumn: driver.DBColumn{Name: "status", Type: "status", TypeName: "status", Nullable: "true"}},
+		{DBColumn: driver.DBColumn{Name: "manager_id", Type: "bigint", TypeName: "int8", Nullable: "true"}},
+		{DBColumn: driver.DBColumn{Name: "age", Type: "integer", TypeName: "int4", Nullable: "true"}},
+		{DBColumn: driver.DBColumn{Name: "decade", Type: "integer", TypeName: "int4", Nullable: "true"}, Generated: "stored", Generation: "(age / 10)"},
+	}, []DBCheck{
+		{Name: "goravel_users_age_check", Definition: "CHECK ((age > 0)) NOT VALID"},
+	}, []DBIndex{
+		{DBIndex: driver.DBIndex{Name: "goravel_users_pkey", Columns: "id", Type: "btree", Primary: true, Unique: true}},
+		{DBIndex: driver.DBIndex{Name: "goravel_users_email_unique", Columns: "email", Type: "btree", Unique: true}, Definition: "CREATE UNIQUE INDEX goravel_users_email_unique ON public.goravel_users USING btree (email)", ConstraintDefinition: "UNIQUE (email)"},
+		{DBIndex: driver.DBIndex{Name: "goravel_users_age_index", Columns: "age", Type: "btree"}, Definition: "CREATE INDEX goravel_users_age_index ON public.goravel_users USING btree (age)"},
+	}, []DBForeignKey{
+		{DBForeignKey: driver.DBForeignKey{Name: "goravel_users_manager_id_foreign", Columns: "manager_id", ForeignSchema: "public", ForeignTable: "goravel_users", ForeignColumns: "id", OnUpdate: "a", OnDelete: "n"}, Match: "f", Deferrable: true, InitiallyDeferred: true},
+	}, []DBTrigger{
+		{
+			Name: "users_touch", Events: `update of "email", "status"`, Timing: "before", ForEach: "row", Statement: "EXECUTE FUNCTION touch()",
+			Definition: "CREATE TRIGGER users_touch BEFORE UPDATE OF email, status ON public.goravel_users FOR EACH ROW EXECUTE FUNCTION touch()",
+		},
+		{
+			Name: "users_truncate", Events: "truncate", Timing: "after", ForEach: "statement", Statement: "EXECUTE FUNCTION touch()",
+			Definition: "CREATE TRIGGER users_truncate AFTER TRUNCATE ON public.goravel_users FOR EACH STATEMENT EXECUTE FUNCTION touch()",
+		},
+	})
+
+	s.mockRaw(s.grammar.CompileViews(""), []View{
+		{View: driver.View{Name: "user_counts", Schema: "public", Definition: " SELECT status, count(*) AS count FROM goravel_users GROUP BY status;"}, Materialized: true,
+			Indexes: `["CREATE UNIQUE INDEX user_counts_status_unique ON public.user_counts USING btree (status)"]`},
+		{View: driver.View{Name: "active_users", Schema: "public", Definition: " SELECT id, email FROM goravel_users WHERE status = 'active'::status;"}},
+		{View: driver.View{Name: "active_user_emails", Schema: "public", Definition: " SELECT email FROM active_users;"}, Dependencies: `[{"schema": "public", "name": "active_users"}]`},
+	})
+	s.mockRaw(`select id, migration, batch from "public"."goravel_migrations" order by id`, []dumpedMigration{
+		{ID: 1, Migration: "20240101000000_create_users_table", Batch: 1},
+		{ID: 2, Migration: "20240102000000_add_status_to_users_table", Batch: 2},
+	})
+}
+
+func (s *DumperTestSuite) mockRaw(sql string, result any) {
+	mockResult := mocksorm.NewQuery(s.T())
+	s.mockQuery.EXPECT().Raw(sql).Return(mockResult).Once()
+	mockResult.EXPECT().Scan(mock.Anything).Run(func(dest any) {
+		reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(result))
+	}).Return(nil).Once()
+}
+
+func (s *DumperTestSuite) mockTable(table string, columns []DBColumn, checks []DBCheck, indexes []DBIndex, foreignKeys []DBForeignKey, triggers []DBTrigger) {
+	sql, err := s.grammar.CompileColumns("public", table)
+	s.Require().NoError(err)
+	s.mockRaw(sql, columns)
+
+	sql, err = s.grammar.CompileChecks("public", table)
+	s.Require().NoError(err)
+	s.mockRaw(sql, checks)
+
+	sql, err = s.grammar.CompileIndexes("public", table)
+	s.Require().NoError(err)
+	s.mockRaw(sql, indexes)
+
+	s.mockRaw(s.grammar.CompileForeignKeys("public", "goravel_"+table), foreignKeys)
+
+	sql, err = s.grammar.CompileTriggers("public", table)
+	s.Require().NoError(err)
+	s.mockRaw(sql, triggers)
+}
@@ -3,6 +3,9 @@ package postgres
 import "github.com/goravel/framework/errors"
 
 var (
-	FailedToGenerateDSN = errors.New("failed to generate DSN, please check the database configuration")
-	ConfigNotFound      = errors.New("not found database configuration")
+	FailedToGenerateDSN  = errors.New("failed to generate DSN, please check the database configuration")
+	ConfigNotFound       = errors.New("not found database configuration")
+	MigrationLintFailed  = errors.New("the migration of table %s has blocking schema changes:\n%s")
+	DumpTypeNotSupported = errors.New("the %s type %s can't be dumped")
+	InvalidThreshold     = errors.New("the %s threshold must be between 0 and 1, got %s")
 )
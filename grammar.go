@@ -16,15 +16,21 @@ import (
 	"github.com/goravel/framework/support/collect"
 	"github.com/spf13/cast"
 	"gorm.io/gorm/clause"
+
+	"github.com/goravel/postgres/contracts"
 )
 
 var _ driver.Grammar = &Grammar{}
 
 type Grammar struct {
 	attributeCommands []string
+	foreignKeyOptions contracts.ForeignKeyOptions
+	idempotent        bool
+	linter            *Linter
 	modifiers         []func(driver.Blueprint, driver.ColumnDefinition) string
 	prefix            string
 	serials           []string
+	tableOptions      map[string]contracts.TableOptions
 	wrap              *schema.Wrap
 }
 
@@ -36,6 +42,9 @@ func NewGrammar(prefix string) *Grammar {
 		wrap:              schema.NewWrap(prefix),
 	}
 	grammar.modifiers = []func(driver.Blueprint, driver.ColumnDefinition) string{
+		grammar.ModifyStorage,
+		grammar.ModifyCompression,
+		grammar.ModifyCollation,
 		grammar.ModifyDefault,
 		grammar.ModifyIncrement,
 		grammar.ModifyNullable,
@@ -46,15 +55,116 @@ func NewGrammar(prefix string) *Grammar {
 	return grammar
 }
 
+func (r *Grammar) CompileAddDomainConstraint(domain string, check contracts.Check) string {
+	sql := fmt.Sprintf("alter domain %s add %s", r.EscapeNames([]string{domain})[0], r.getCheck(check))
+	if check.NotValid {
+		sql += " not valid"
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileAddForeignKey(foreignKey contracts.ForeignKey) string {
+	sql := fmt.Sprintf("alter table %s add constraint %s foreign key (%s) references %s (%s)",
+		r.wrap.Table(foreignKey.Table),
+		r.wrap.Column(foreignKey.Name),
+		r.wrap.Columnize(foreignKey.Columns),
+		r.wrap.Table(foreignKey.On),
+		r.wrap.Columnize(foreignKey.References))
+	if foreignKey.Match != "" {
+		sql += " match " + foreignKey.Match
+	}
+	if foreignKey.OnDelete != "" {
+		sql += " on delete " + foreignKey.OnDelete
+	}
+	if foreignKey.OnUpdate != "" {
+		sql += " on update " + foreignKey.OnUpdate
+	}
+	sql += r.getDeferrable(foreignKey.Deferrable, foreignKey.InitiallyImmediate)
+	if foreignKey.NotValid {
+		sql += " not valid"
+	}
+
+	return r.guardConstraint(foreignKey.Table, "conname = "+r.quoteString(foreignKey.Name), sql)
+}
+
+func (r *Grammar) CompileAddCheck(table string, check contracts.Check) string {
+	sql := fmt.Sprintf("alter table %s add %s", r.wrap.Table(table), r.getCheck(check))
+	if check.NotValid {
+		sql += " not valid"
+	}
+	if check.Name != "" {
+		return r.guardConstraint(table, "conname = "+r.quoteString(check.Name), sql)
+	}
+
+	return sql
+}
+
 func (r *Grammar) CompileAdd(blueprint driver.Blueprint, command *driver.Command) string {
-	return fmt.Sprintf("alter table %s add column %s", r.wrap.Table(blueprint.GetTableName()), r.getColumn(blueprint, command.Column))
+	return r.lintStatement(blueprint, command,
+		fmt.Sprintf("alter table %s add column %s%s", r.wrap.Table(blueprint.GetTableName()), r.ifNotExists(), r.getColumn(blueprint, command.Column)))
+}
+
+// CompileAlterColumnType changes the type of a column, the bool reports whether the change rewrites the table,
+// which blocks reads and writes of the table until it's done. The rewrite is assumed when the current type is unknown.
+func (r *Grammar) CompileAlterColumnType(change contracts.ColumnTypeChange) (string, bool) {
+	ttype, attributes := r.getType(schema.NewColumnDefinition(change.Column, change.Type))
+	sql := fmt.Sprintf("alter table %s alter column %s type %s", r.wrap.Table(change.Table), r.wrap.Column(change.Column), ttype)
+	if attributes.collation != "" {
+		sql += " collate " + r.wrap.Value(attributes.collation)
+	}
+
+	using := change.Using
+	if using == "" && change.From != "" {
+		using = r.getUsing(change.Column, change.From, ttype)
+	}
+	if using != "" {
+		sql += " using " + using
+	}
+
+	return sql, change.From == "" || using != "" || requiresRewrite(change.From, ttype)
+}
+
+func (r *Grammar) CompileAlterPolicy(policy contracts.Policy) string {
+	return fmt.Sprintf("alter policy %s on %s", r.wrap.Value(policy.Name), r.wrap.Table(policy.Table)) + r.getPolicyOptions(policy)
+}
+
+func (r *Grammar) CompileAlterRole(role contracts.Role) string {
+	sql := "alter role " + r.wrap.Value(role.Name)
+	if options := r.getRoleOptions(role); len(options) > 0 {
+		sql += " with " + strings.Join(options, " ")
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileAlterSequence(sequence contracts.Sequence) string {
+	sql := "alter sequence " + r.EscapeNames([]string{sequence.Name})[0]
+	if options := r.getSequenceOptions(sequence); len(options) > 0 {
+		sql += " " + strings.Join(options, " ")
+	}
+
+	return sql
 }
 
 func (r *Grammar) CompileChange(blueprint driver.Blueprint, command *driver.Command) []string {
-	changes := []string{fmt.Sprintf("alter column %s type %s", r.wrap.Column(command.Column.GetName()), schema.ColumnType(r, command.Column))}
+	if sql := r.lintStatement(blueprint, command, ""); sql != "" {
+		return []string{sql}
+	}
+
+	ttype, attributes := r.getType(command.Column)
+	if attributes.collation != "" {
+		ttype += " collate " + r.wrap.Value(attributes.collation)
+	}
+	if attributes.using != "" {
+		ttype += " using " + attributes.using
+	}
+
+	column := &attributedColumn{ColumnDefinition: command.Column, attributes: attributes}
+	changes := []string{fmt.Sprintf("alter column %s type %s", r.wrap.Column(column.GetName()), ttype)}
 	for _, modifier := range r.modifiers {
-		if change := modifier(blueprint, command.Column); change != "" {
-			changes = append(changes, fmt.Sprintf("alter column %s%s", r.wrap.Column(command.Column.GetName()), change))
+		if change := modifier(blueprint, column); change != "" {
+			changes = append(changes, fmt.Sprintf("alter column %s%s", r.wrap.Column(column.GetName()), change))
 		}
 	}
 
@@ -63,6 +173,26 @@ func (r *Grammar) CompileChange(blueprint driver.Blueprint, command *driver.Comm
 	}
 }
 
+func (r *Grammar) CompileChecks(schema, table string) (string, error) {
+	schema, table, err := parseSchemaAndTable(table, schema)
+	if err != nil {
+		return "", err
+	}
+
+	table = r.prefix + table
+
+	return fmt.Sprintf(
+		"select con.conname as name, pg_get_constraintdef(con.oid) as definition "+
+			"from pg_constraint con "+
+			"join pg_class c on c.oid = con.conrelid "+
+			"join pg_namespace n on n.oid = c.relnamespace "+
+			"where con.contype = 'c' and c.relname = %s and n.nspname = %s "+
+			"order by con.conname",
+		r.wrap.Quote(table),
+		r.wrap.Quote(schema),
+	), nil
+}
+
 func (r *Grammar) CompileColumns(schema, table string) (string, error) {
 	schema, table, err := parseSchemaAndTable(table, schema)
 	if err != nil {
@@ -73,12 +203,21 @@ func (r *Grammar) CompileColumns(schema, table string) (string, error) {
 
 	return fmt.Sprintf(
 		"select a.attname as name, t.typname as type_name, format_type(a.atttypid, a.atttypmod) as type, "+
-			"(select tc.collcollate from pg_catalog.pg_collation tc where tc.oid = a.attcollation) as collation, "+
+			"(select tc.collname from pg_catalog.pg_collation tc where tc.oid = a.attcollation and a.attcollation <> t.typcollation) as collation, "+
+			"case a.attcompression when 'p' then 'pglz' when 'l' then 'lz4' end as compression, "+
+			"case when a.attstorage <> t.typstorage then case a.attstorage when 'p' then 'plain' when 'e' then 'external' "+
+			"when 'm' then 'main' when 'x' then 'extended' end end as storage, "+
 			"not a.attnotnull as nullable, "+
-			"(select pg_get_expr(adbin, adrelid) from pg_attrdef where c.oid = pg_attrdef.adrelid and pg_attrdef.adnum = a.attnum) as default, "+
+			"case when a.attgenerated = '' then pg_get_expr(d.adbin, d.adrelid) end as default, "+
+			"case when a.attgenerated <> '' then pg_get_expr(d.adbin, d.adrelid) end as generation, "+
+			"case a.attgenerated when 's' then 'stored' when 'v' then 'virtual' end as generated, "+
+			"case a.attidentity when 'a' then 'always' when 'd' then 'by default' end as identity, "+
+			"a.attidentity <> '' as autoincrement, a.attndims as dimensions, "+
+			"case when t.typtype = 'd' then format_type(t.typbasetype, t.typtypmod) end as base_type, "+
 			"col_description(c.oid, a.attnum) as comment "+
-			"from pg_attribute a, pg_class c, pg_type t, pg_namespace n "+
-			"where c.relname = %s and n.nspname = %s and a.attnum > 0 and a.attrelid = c.oid and a.atttypid = t.oid and n.oid = c.relnamespace "+
+			"from pg_attribute a join pg_class c on c.oid = a.attrelid join pg_namespace n on n.oid = c.relnamespace "+
+			"join pg_type t on t.oid = a.atttypid left join pg_attrdef d on d.adrelid = a.attrelid and d.adnum = a.attnum "+
+			"where c.relname = %s and n.nspname = %s and a.attnum > 0 "+
 			"order by a.attnum", r.wrap.Quote(table), r.wrap.Quote(schema)), nil
 }
 
@@ -95,21 +234,258 @@ func (r *Grammar) CompileComment(blueprint driver.Blueprint, command *driver.Com
 }
 
 func (r *Grammar) CompileCreate(blueprint driver.Blueprint) string {
-	return fmt.Sprintf("create table %s (%s)", r.wrap.Table(blueprint.GetTableName()), strings.Join(r.getColumns(blueprint), ", "))
+	table := blueprint.GetTableName()
+
+	return r.compileCreate(table, r.getColumns(blueprint), r.tableOptions[table])
+}
+
+func (r *Grammar) CompileCreateDomain(domain contracts.Domain) string {
+	ttype, _ := r.getType(schema.NewColumnDefinition("", domain.Type))
+	sql := fmt.Sprintf("create domain %s as %s", r.EscapeNames([]string{domain.Name})[0], ttype)
+	if domain.Collation != "" {
+		sql += " collate " + r.wrap.Value(domain.Collation)
+	}
+	if domain.Default != nil {
+		sql += " default " + r.getDefaultValue(domain.Default)
+	}
+	if domain.NotNull {
+		sql += " not null"
+	}
+	for _, check := range domain.Checks {
+		sql += " " + r.getCheck(check)
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileCreateExtension(extension contracts.Extension) string {
+	sql := fmt.Sprintf("create extension if not exists %s", r.wrap.Value(extension.Name))
+	if extension.Schema != "" {
+		sql += " schema " + r.wrap.Value(extension.Schema)
+	}
+	if extension.Version != "" {
+		sql += " version " + r.quoteString(extension.Version)
+	}
+	if extension.Cascade {
+		sql += " cascade"
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileCreateFunction(function contracts.Function) string {
+	sql := "create "
+	if function.OrReplace {
+		sql += "or replace "
+	}
+
+	language := "plpgsql"
+	if function.Language != "" {
+		language = function.Language
+	}
+
+	sql += fmt.Sprintf("function %s(%s) returns %s language %s", r.EscapeNames([]string{function.Name})[0], function.Arguments, function.Returns, language)
+	if function.Volatility != "" {
+		sql += " " + function.Volatility
+	}
+	if function.SecurityDefiner {
+		sql += " security definer"
+	}
+
+	return sql + " as $function$" + function.Body + "$function$"
+}
+
+func (r *Grammar) CompileCreateIndex(index contracts.Index) string {
+	sql := "create "
+	if index.Unique {
+		sql += "unique "
+	}
+	sql += "index "
+	if index.Concurrently {
+		sql += "concurrently "
+	}
+
+	sql += fmt.Sprintf("%s%s on %s", r.ifNotExists(), r.wrap.Column(index.Name), r.wrap.Table(index.Table))
+	if index.Algorithm != "" {
+		sql += " using " + index.Algorithm
+	}
+	sql += fmt.Sprintf(" (%s)", strings.Join(collect.Map(index.Columns, func(column string, _ int) string {
+		if index.OperatorClass != "" {
+			return r.wrap.Column(column) + " " + index.OperatorClass
+		}
+
+		return r.wrap.Column(column)
+	}), ", "))
+	if len(index.With) > 0 {
+		sql += fmt.Sprintf(" with (%s)", r.getStorageParameters(index.With))
+	}
+	if index.Where != "" {
+		sql += " where " + index.Where
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileCreateMaterializedView(view contracts.MaterializedView) string {
+	data := "with data"
+	if view.NoData {
+		data = "with no data"
+	}
+
+	return fmt.Sprintf("create materialized view %s as %s %s", r.EscapeNames([]string{view.Name})[0], view.Query, data)
+}
+
+func (r *Grammar) CompileCreatePolicy(policy contracts.Policy) string {
+	sql := fmt.Sprintf("create policy %s on %s", r.wrap.Value(policy.Name), r.wrap.Table(policy.Table))
+	if policy.As != "" {
+		sql += " as " + policy.As
+	}
+	if policy.Command != "" {
+		sql += " for " + policy.Command
+	}
+
+	return sql + r.getPolicyOptions(policy)
+}
+
+func (r *Grammar) CompileCreateRangeType(rangeType contracts.RangeType) string {
+	options := []string{"subtype = " + rangeType.Subtype}
+	if rangeType.SubtypeOpClass != "" {
+		options = append(options, "subtype_opclass = "+rangeType.SubtypeOpClass)
+	}
+	if rangeType.Collation != "" {
+		options = append(options, "collation = "+r.wrap.Value(rangeType.Collation))
+	}
+	if rangeType.Canonical != "" {
+		options = append(options, "canonical = "+rangeType.Canonical)
+	}
+	if rangeType.SubtypeDiff != "" {
+		options = append(options, "subtype_diff = "+rangeType.SubtypeDiff)
+	}
+	if rangeType.MultirangeTypeName != "" {
+		options = append(options, "multirange_type_name = "+r.EscapeNames([]string{rangeType.MultirangeTypeName})[0])
+	}
+
+	return fmt.Sprintf("create type %s as range (%s)", r.EscapeNames([]string{rangeType.Name})[0], strings.Join(options, ", "))
+}
+
+func (r *Grammar) CompileCreateRole(role contracts.Role) string {
+	sql := "create role " + r.wrap.Value(role.Name)
+	options := r.getRoleOptions(role)
+	if len(role.InRoles) > 0 {
+		options = append(options, "in role "+r.getRoles(role.InRoles))
+	}
+	if len(options) > 0 {
+		sql += " with " + strings.Join(options, " ")
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileCreateSchema(schema contracts.Schema) string {
+	sql := "create schema "
+	if schema.IfNotExists {
+		sql += "if not exists "
+	}
+	sql += r.wrap.Value(schema.Name)
+	if schema.Authorization != "" {
+		sql += " authorization " + r.wrap.Value(schema.Authorization)
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileCreateSequence(sequence contracts.Sequence) string {
+	sql := "create sequence "
+	if sequence.IfNotExists {
+		sql += "if not exists "
+	}
+	sql += r.EscapeNames([]string{sequence.Name})[0]
+	if options := r.getSequenceOptions(sequence); len(options) > 0 {
+		sql += " " + strings.Join(options, " ")
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileCreateTable(blueprint driver.Blueprint, options contracts.TableOptions) string {
+	return r.compileCreate(blueprint.GetTableName(), r.getColumns(blueprint), options)
+}
+
+// CompileCreateTextSearchConfiguration creates a text search configuration and maps its token types to the dictionaries,
+// e.g. to unaccent the words before stemming them, which requires the unaccent extension.
+func (r *Grammar) CompileCreateTextSearchConfiguration(configuration contracts.TextSearchConfiguration) []string {
+	name := r.EscapeNames([]string{configuration.Name})[0]
+	sqls := []string{fmt.Sprintf("create text search configuration %s (copy = %s)", name, r.EscapeNames([]string{configuration.Copy})[0])}
+
+	tokenTypes := make([]string, 0, len(configuration.Mappings))
+	for tokenType := range configuration.Mappings {
+		tokenTypes = append(tokenTypes, tokenType)
+	}
+	slices.Sort(tokenTypes)
+
+	for _, tokenType := range tokenTypes {
+		sqls = append(sqls, fmt.Sprintf("alter text search configuration %s alter mapping for %s with %s",
+			name, tokenType, strings.Join(r.EscapeNames(configuration.Mappings[tokenType]), ", ")))
+	}
+
+	return sqls
+}
+
+func (r *Grammar) CompileCreateTrigger(trigger contracts.Trigger) string {
+	sql := "create "
+	if trigger.OrReplace {
+		sql += "or replace "
+	}
+
+	events := collect.Map(trigger.Events, func(event string, _ int) string {
+		if columns, ok := strings.CutPrefix(event, "update of "); ok {
+			return "update of " + r.wrap.Columnize(strings.Split(strings.ReplaceAll(columns, " ", ""), ","))
+		}
+
+		return event
+	})
+
+	forEach := "row"
+	if trigger.ForEach != "" {
+		forEach = trigger.ForEach
+	}
+
+	sql += fmt.Sprintf("trigger %s %s %s on %s for each %s", r.wrap.Value(trigger.Name), trigger.Timing, strings.Join(events, " or "), r.wrap.Table(trigger.Table), forEach)
+	if trigger.When != "" {
+		sql += fmt.Sprintf(" when (%s)", trigger.When)
+	}
+
+	arguments := collect.Map(trigger.Arguments, func(argument string, _ int) string {
+		return r.quoteString(argument)
+	})
+
+	return sql + fmt.Sprintf(" execute function %s(%s)", r.EscapeNames([]string{trigger.Function})[0], strings.Join(arguments, ", "))
 }
 
 func (r *Grammar) CompileDefault(_ driver.Blueprint, _ *driver.Command) string {
 	return ""
 }
 
+func (r *Grammar) CompileDisableRowLevelSecurity(table string) string {
+	return fmt.Sprintf("alter table %s disable row level security", r.wrap.Table(table))
+}
+
 func (r *Grammar) CompileDrop(blueprint driver.Blueprint) string {
-	return fmt.Sprintf("drop table %s", r.wrap.Table(blueprint.GetTableName()))
+	return fmt.Sprintf("drop table %s%s", r.ifExists(), r.wrap.Table(blueprint.GetTableName()))
 }
 
 func (r *Grammar) CompileDropAllDomains(domains []string) string {
 	return fmt.Sprintf("drop domain %s cascade", strings.Join(r.EscapeNames(domains), ", "))
 }
 
+func (r *Grammar) CompileDropDomain(name string) string {
+	return fmt.Sprintf("drop domain if exists %s", r.EscapeNames([]string{name})[0])
+}
+
+func (r *Grammar) CompileDropDomainConstraint(domain, constraint string) string {
+	return fmt.Sprintf("alter domain %s drop constraint if exists %s", r.EscapeNames([]string{domain})[0], r.wrap.Value(constraint))
+}
+
 func (r *Grammar) CompileDropAllTables(schema string, tables []driver.Table) []string {
 	excludedTables := r.EscapeNames([]string{"spatial_ref_sys"})
 	escapedSchema := r.EscapeNames([]string{schema})[0]
@@ -157,22 +533,36 @@ func (r *Grammar) CompileDropAllTypes(schema string, types []driver.Type) []stri
 	return sql
 }
 
+// CompileDropAllViews drops views and materialized views, driver.View doesn't tell them apart,
+// so the kind of each view is looked up when the statement runs.
 func (r *Grammar) CompileDropAllViews(schema string, views []driver.View) []string {
 	var dropViews []string
 	for _, view := range views {
 		if schema == view.Schema {
-			dropViews = append(dropViews, fmt.Sprintf("%s.%s", view.Schema, view.Name))
+			dropViews = append(dropViews, fmt.Sprintf("(%s, %s)", r.quoteString(view.Schema), r.quoteString(view.Name)))
 		}
 	}
 	if len(dropViews) == 0 {
 		return nil
 	}
 
-	return []string{fmt.Sprintf("drop view %s cascade", strings.Join(r.EscapeNames(dropViews), ", "))}
+	return []string{fmt.Sprintf("do $$ declare v record; begin "+
+		"for v in select n.nspname, c.relname, c.relkind from pg_class c join pg_namespace n on n.oid = c.relnamespace "+
+		"where c.relkind in ('v', 'm') and (n.nspname, c.relname) in (%s) loop "+
+		"execute format('drop %%s if exists %%I.%%I cascade', case v.relkind when 'm' then 'materialized view' else 'view' end, v.nspname, v.relname); "+
+		"end loop; end $$", strings.Join(dropViews, ", "))}
 }
 
 func (r *Grammar) CompileDropColumn(blueprint driver.Blueprint, command *driver.Command) []string {
-	columns := r.wrap.PrefixArray("drop column", r.wrap.Columns(command.Columns))
+	if sql := r.lintStatement(blueprint, command, ""); sql != "" {
+		return []string{sql}
+	}
+
+	prefix := "drop column"
+	if r.idempotent {
+		prefix += " if exists"
+	}
+	columns := r.wrap.PrefixArray(prefix, r.wrap.Columns(command.Columns))
 
 	return []string{
 		fmt.Sprintf("alter table %s %s", r.wrap.Table(blueprint.GetTableName()), strings.Join(columns, ", ")),
@@ -180,49 +570,130 @@ func (r *Grammar) CompileDropColumn(blueprint driver.Blueprint, command *driver.
 }
 
 func (r *Grammar) CompileDropForeign(blueprint driver.Blueprint, command *driver.Command) string {
-	return fmt.Sprintf("alter table %s drop constraint %s", r.wrap.Table(blueprint.GetTableName()), r.wrap.Column(command.Index))
+	return r.lintStatement(blueprint, command, fmt.Sprintf("alter table %s drop constraint %s%s", r.wrap.Table(blueprint.GetTableName()), r.ifExists(), r.wrap.Column(command.Index)))
+}
+
+func (r *Grammar) CompileDropExtension(extension contracts.Extension) string {
+	sql := fmt.Sprintf("drop extension if exists %s", r.wrap.Value(extension.Name))
+	if extension.Cascade {
+		sql += " cascade"
+	}
+
+	return sql
 }
 
 func (r *Grammar) CompileDropFullText(blueprint driver.Blueprint, command *driver.Command) string {
 	return r.CompileDropIndex(blueprint, command)
 }
 
+func (r *Grammar) CompileDropFunction(function contracts.Function) string {
+	sql := fmt.Sprintf("drop function if exists %s", r.EscapeNames([]string{function.Name})[0])
+	if function.Arguments != "" {
+		sql += "(" + function.Arguments + ")"
+	}
+	if function.Cascade {
+		sql += " cascade"
+	}
+
+	return sql
+}
+
 func (r *Grammar) CompileDropIfExists(blueprint driver.Blueprint) string {
 	return fmt.Sprintf("drop table if exists %s", r.wrap.Table(blueprint.GetTableName()))
 }
 
 func (r *Grammar) CompileDropIndex(blueprint driver.Blueprint, command *driver.Command) string {
-	return fmt.Sprintf("drop index %s", r.wrap.Column(command.Index))
+	return r.lintStatement(blueprint, command, fmt.Sprintf("drop index %s%s", r.ifExists(), r.wrap.Column(command.Index)))
+}
+
+func (r *Grammar) CompileDropMaterializedView(name string) string {
+	return fmt.Sprintf("drop materialized view if exists %s", r.EscapeNames([]string{name})[0])
+}
+
+func (r *Grammar) CompileDropPolicy(table, name string) string {
+	return fmt.Sprintf("drop policy if exists %s on %s", r.wrap.Value(name), r.wrap.Table(table))
 }
 
 func (r *Grammar) CompileDropPrimary(blueprint driver.Blueprint, command *driver.Command) string {
 	tableName := blueprint.GetTableName()
 	index := r.wrap.Column(fmt.Sprintf("%s%s_pkey", r.wrap.GetPrefix(), tableName))
 
-	return fmt.Sprintf("alter table %s drop constraint %s", r.wrap.Table(tableName), index)
+	return r.lintStatement(blueprint, command, fmt.Sprintf("alter table %s drop constraint %s%s", r.wrap.Table(tableName), r.ifExists(), index))
 }
 
-func (r *Grammar) CompileDropUnique(blueprint driver.Blueprint, command *driver.Command) string {
-	return fmt.Sprintf("alter table %s drop constraint %s", r.wrap.Table(blueprint.GetTableName()), r.wrap.Column(command.Index))
+func (r *Grammar) CompileDropRole(name string) string {
+	return "drop role if exists " + r.wrap.Value(name)
 }
 
-func (r *Grammar) CompileForeign(blueprint driver.Blueprint, command *driver.Command) string {
-	sql := fmt.Sprintf("alter table %s add constraint %s foreign key (%s) references %s (%s)",
-		r.wrap.Table(blueprint.GetTableName()),
-		r.wrap.Column(command.Index),
-		r.wrap.Columnize(command.Columns),
-		r.wrap.Table(command.On),
-		r.wrap.Columnize(command.References))
-	if command.OnDelete != "" {
-		sql += " on delete " + command.OnDelete
+func (r *Grammar) CompileDropSchema(schema contracts.Schema) string {
+	sql := fmt.Sprintf("drop schema if exists %s", r.wrap.Value(schema.Name))
+	if schema.Cascade {
+		sql += " cascade"
 	}
-	if command.OnUpdate != "" {
-		sql += " on update " + command.OnUpdate
+
+	return sql
+}
+
+func (r *Grammar) CompileDropSequence(sequence contracts.Sequence) string {
+	sql := fmt.Sprintf("drop sequence if exists %s", r.EscapeNames([]string{sequence.Name})[0])
+	if sequence.Cascade {
+		sql += " cascade"
 	}
 
 	return sql
 }
 
+func (r *Grammar) CompileDropTextSearchConfiguration(name string) string {
+	return fmt.Sprintf("drop text search configuration if exists %s", r.EscapeNames([]string{name})[0])
+}
+
+func (r *Grammar) CompileDropTrigger(table, name string) string {
+	return fmt.Sprintf("drop trigger if exists %s on %s", r.wrap.Value(name), r.wrap.Table(table))
+}
+
+func (r *Grammar) CompileDropType(name string) string {
+	return fmt.Sprintf("drop type if exists %s", r.EscapeNames([]string{name})[0])
+}
+
+func (r *Grammar) CompileDropUnique(blueprint driver.Blueprint, command *driver.Command) string {
+	return r.lintStatement(blueprint, command, fmt.Sprintf("alter table %s drop constraint %s%s", r.wrap.Table(blueprint.GetTableName()), r.ifExists(), r.wrap.Column(command.Index)))
+}
+
+func (r *Grammar) CompileEnableRowLevelSecurity(table string) string {
+	return fmt.Sprintf("alter table %s enable row level security", r.wrap.Table(table))
+}
+
+func (r *Grammar) CompileExtensions() string {
+	return "select e.extname as name, n.nspname as schema, e.extversion as version " +
+		"from pg_extension e join pg_namespace n on n.oid = e.extnamespace " +
+		"order by e.extname"
+}
+
+// CompileForceRowLevelSecurity applies the policies of a table to its owner as well, pass false to exempt the owner again.
+func (r *Grammar) CompileForceRowLevelSecurity(table string, force bool) string {
+	if force {
+		return fmt.Sprintf("alter table %s force row level security", r.wrap.Table(table))
+	}
+
+	return fmt.Sprintf("alter table %s no force row level security", r.wrap.Table(table))
+}
+
+func (r *Grammar) CompileForeign(blueprint driver.Blueprint, command *driver.Command) string {
+	return r.lintStatement(blueprint, command, r.CompileAddForeignKey(contracts.ForeignKey{
+		Table:              blueprint.GetTableName(),
+		Name:               command.Index,
+		Columns:            command.Columns,
+		On:                 command.On,
+		References:         command.References,
+		OnDelete:           command.OnDelete,
+		OnUpdate:           command.OnUpdate,
+		Match:              r.foreignKeyOptions.Match,
+		Deferrable:         command.Deferrable,
+		InitiallyImmediate: command.InitiallyImmediate,
+		NotValid:           r.foreignKeyOptions.NotValid,
+	}))
+}
+
 func (r *Grammar) CompileForeignKeys(schema, table string) string {
 	return fmt.Sprintf(
 		`SELECT 
@@ -232,7 +703,10 @@ func (r *Grammar) CompileForeignKeys(schema, table string) string {
 			fc.relname AS foreign_table, 
 			string_agg(fa.attname, ',' ORDER BY conseq.ord) AS foreign_columns, 
 			c.confupdtype AS on_update, 
-			c.confdeltype AS on_delete 
+			c.confdeltype AS on_delete, 
+			c.confmatchtype AS match, 
+			c.condeferrable AS deferrable, 
+			c.condeferred AS initially_deferred 
 		FROM pg_constraint c 
 		JOIN pg_class tc ON c.conrelid = tc.oid 
 		JOIN pg_namespace tn ON tn.oid = tc.relnamespace 
@@ -242,32 +716,118 @@ func (r *Grammar) CompileForeignKeys(schema, table string) string {
 		JOIN pg_attribute la ON la.attrelid = c.conrelid AND la.attnum = conseq.num 
 		JOIN pg_attribute fa ON fa.attrelid = c.confrelid AND fa.attnum = c.confkey[conseq.ord] 
 		WHERE c.contype = 'f' AND tc.relname = %s AND tn.nspname = %s 
-		GROUP BY c.conname, fn.nspname, fc.relname, c.confupdtype, c.confdeltype`,
+		GROUP BY c.conname, fn.nspname, fc.relname, c.confupdtype, c.confdeltype, c.confmatchtype, c.condeferrable, c.condeferred`,
 		r.wrap.Quote(table),
 		r.wrap.Quote(schema),
 	)
 }
 
 func (r *Grammar) CompileFullText(blueprint driver.Blueprint, command *driver.Command) string {
+	return r.lintStatement(blueprint, command, r.compileFullText(blueprint, command))
+}
+
+func (r *Grammar) compileFullText(blueprint driver.Blueprint, command *driver.Command) string {
 	language := "english"
 	if command.Language != "" {
 		language = command.Language
 	}
 
-	columns := collect.Map(command.Columns, func(column string, _ int) string {
-		return fmt.Sprintf("to_tsvector(%s, %s)", r.wrap.Quote(language), r.wrap.Column(column))
-	})
+	return fmt.Sprintf("create index %s%s on %s using gin(%s)", r.ifNotExists(), r.wrap.Column(command.Index), r.wrap.Table(blueprint.GetTableName()), r.getTsVector(language, command.Columns, nil))
+}
+
+// CompileFullTextHeadline highlights the matches of the query in a column, the options are the ones of ts_headline,
+// e.g. {"StartSel": "<b>", "StopSel": "</b>", "MaxWords": 35}.
+func (r *Grammar) CompileFullTextHeadline(search contracts.FullTextSearch, column, query string, options map[string]any) (string, []any) {
+	sql := fmt.Sprintf("ts_headline(%s, %s, %s", r.wrap.Quote(r.getTsLanguage(search)), r.wrap.Column(column), r.getTsQuery(search))
+	if len(options) > 0 {
+		sql += ", " + r.quoteString(r.getStorageParameters(options))
+	}
+
+	return sql + ")", []any{query}
+}
+
+// CompileFullTextMatch matches the columns with the same expression as CompileFullText, so the full text index is used.
+func (r *Grammar) CompileFullTextMatch(search contracts.FullTextSearch, query string, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("(%s) @@ %s", r.getTsVector(r.getTsLanguage(search), search.Columns, nil), r.getTsQuery(search)), isNot), []any{query}
+}
+
+func (r *Grammar) CompileFullTextOrderByRank(search contracts.FullTextSearch, query string) (string, []any) {
+	sql, bindings := r.CompileFullTextRank(search, query)
+
+	return sql + " desc", bindings
+}
+
+// CompileFullTextRank ranks the matches of the query by the weights of the columns, the columns without a weight are weighted D.
+func (r *Grammar) CompileFullTextRank(search contracts.FullTextSearch, query string) (string, []any) {
+	function := "ts_rank"
+	if search.CoverDensity {
+		function = "ts_rank_cd"
+	}
+
+	sql := fmt.Sprintf("%s(%s, %s", function, r.getTsVector(r.getTsLanguage(search), search.Columns, search.Weights), r.getTsQuery(search))
+	if search.Normalization > 0 {
+		sql += fmt.Sprintf(", %d", search.Normalization)
+	}
+
+	return sql + ")", []any{query}
+}
+
+// CompileFunctions returns the functions and the procedures except the ones of the extensions.
+func (r *Grammar) CompileFunctions() string {
+	return "select p.proname as name, n.nspname as schema, pg_get_function_arguments(p.oid) as arguments, " +
+		"pg_get_function_result(p.oid) as returns, l.lanname as language, p.provolatile as volatility, " +
+		"p.prosecdef as security_definer, p.prokind = 'p' as procedure, pg_get_functiondef(p.oid) as definition, " +
+		"p.oid::regprocedure::text as signature, " +
+		"(select json_agg(distinct d.refobjid::regprocedure::text) from pg_depend d " +
+		"where d.classid = 'pg_proc'::regclass and d.objid = p.oid and d.refclassid = 'pg_proc'::regclass and d.refobjid <> p.oid) as dependencies, " +
+		"exists (select 1 from pg_depend d " +
+		"left join pg_type dt on d.refclassid = 'pg_type'::regclass and dt.oid = d.refobjid " +
+		"join pg_class dc on dc.oid = case when d.refclassid = 'pg_class'::regclass then d.refobjid else dt.typrelid end " +
+		"where d.classid = 'pg_proc'::regclass and d.objid = p.oid and dc.relkind in ('r', 'p')) as uses_tables, " +
+		"exists (select 1 from pg_depend d " +
+		"left join pg_type dt on d.refclassid = 'pg_type'::regclass and dt.oid = d.refobjid " +
+		"join pg_class dc on dc.oid = case when d.refclassid = 'pg_class'::regclass then d.refobjid else dt.typrelid end " +
+		"where d.classid = 'pg_proc'::regclass and d.objid = p.oid and dc.relkind in ('v', 'm')) as uses_views " +
+		"from pg_proc p " +
+		"join pg_namespace n on n.oid = p.pronamespace " +
+		"join pg_language l on l.oid = p.prolang " +
+		"where p.prokind in ('f', 'p') and n.nspname not in ('pg_catalog', 'information_schema') " +
+		"and not exists (select 1 from pg_depend d where d.objid = p.oid and d.deptype = 'e') " +
+		"order by p.proname"
+}
+
+func (r *Grammar) CompileGrant(grant contracts.Grant) string {
+	sql := fmt.Sprintf("grant %s on %s to %s", r.getPrivileges(grant), r.getGrantObjects(grant), r.getRoles(grant.Roles))
+	if grant.WithGrantOption {
+		sql += " with grant option"
+	}
+
+	return sql
+}
 
-	return fmt.Sprintf("create index %s on %s using gin(%s)", r.wrap.Column(command.Index), r.wrap.Table(blueprint.GetTableName()), strings.Join(columns, " || "))
+// CompileGrantDefaultPrivileges grants privileges on the objects that the owner creates in the schema from now on,
+// the objects are all the objects of the type, so the objects of the grant are ignored.
+func (r *Grammar) CompileGrantDefaultPrivileges(owner, schema string, grant contracts.Grant) string {
+	sql := fmt.Sprintf("grant %s on %s to %s", r.getPrivileges(grant), r.getDefaultPrivilegesObjectType(grant), r.getRoles(grant.Roles))
+	if grant.WithGrantOption {
+		sql += " with grant option"
+	}
+
+	return r.getAlterDefaultPrivileges(owner, schema) + sql
 }
 
 func (r *Grammar) CompileIndex(blueprint driver.Blueprint, command *driver.Command) string {
+	return r.lintStatement(blueprint, command, r.compileIndex(blueprint, command))
+}
+
+func (r *Grammar) compileIndex(blueprint driver.Blueprint, command *driver.Command) string {
 	var algorithm string
 	if command.Algorithm != "" {
 		algorithm = " using " + command.Algorithm
 	}
 
-	return fmt.Sprintf("create index %s on %s%s (%s)",
+	return fmt.Sprintf("create index %s%s on %s%s (%s)",
+		r.ifNotExists(),
 		r.wrap.Column(command.Index),
 		r.wrap.Table(blueprint.GetTableName()),
 		algorithm,
@@ -285,7 +845,8 @@ func (r *Grammar) CompileIndexes(schema, table string) (string, error) {
 
 	return fmt.Sprintf(
 		"select ic.relname as name, string_agg(a.attname, ',' order by indseq.ord) as columns, "+
-			"am.amname as \"type\", i.indisunique as \"unique\", i.indisprimary as \"primary\" "+
+			"am.amname as \"type\", i.indisunique as \"unique\", i.indisprimary as \"primary\", pg_get_indexdef(i.indexrelid) as definition, "+
+			"pg_get_constraintdef(con.oid) as constraint_definition "+
 			"from pg_index i "+
 			"join pg_class tc on tc.oid = i.indrelid "+
 			"join pg_namespace tn on tn.oid = tc.relnamespace "+
@@ -293,8 +854,9 @@ func (r *Grammar) CompileIndexes(schema, table string) (string, error) {
 			"join pg_am am on am.oid = ic.relam "+
 			"join lateral unnest(i.indkey) with ordinality as indseq(num, ord) on true "+
 			"left join pg_attribute a on a.attrelid = i.indrelid and a.attnum = indseq.num "+
+			"left join pg_constraint con on con.conindid = i.indexrelid and con.conrelid = i.indrelid and con.contype in ('p', 'u', 'x') "+
 			"where tc.relname = %s and tn.nspname = %s "+
-			"group by ic.relname, am.amname, i.indisunique, i.indisprimary",
+			"group by ic.relname, am.amname, i.indisunique, i.indisprimary, i.indexrelid, con.oid",
 		r.wrap.Quote(table),
 		r.wrap.Quote(schema),
 	), nil
@@ -441,8 +1003,30 @@ func (r *Grammar) CompilePlaceholderFormat() driver.PlaceholderFormat {
 	return sq.Dollar
 }
 
+func (r *Grammar) CompilePolicies(schema, table string) (string, error) {
+	schema, table, err := parseSchemaAndTable(table, schema)
+	if err != nil {
+		return "", err
+	}
+
+	table = r.prefix + table
+
+	return fmt.Sprintf(
+		"select policyname as name, permissive as \"as\", cmd as command, array_to_string(roles, ',') as roles, "+
+			"qual as \"using\", with_check "+
+			"from pg_policies "+
+			"where tablename = %s and schemaname = %s "+
+			"order by policyname",
+		r.wrap.Quote(table),
+		r.wrap.Quote(schema),
+	), nil
+}
+
 func (r *Grammar) CompilePrimary(blueprint driver.Blueprint, command *driver.Command) string {
-	return fmt.Sprintf("alter table %s add primary key (%s)", r.wrap.Table(blueprint.GetTableName()), r.wrap.Columnize(command.Columns))
+	table := blueprint.GetTableName()
+
+	return r.lintStatement(blueprint, command,
+		r.guardConstraint(table, "contype = 'p'", fmt.Sprintf("alter table %s add primary key (%s)", r.wrap.Table(table), r.wrap.Columnize(command.Columns))))
 }
 
 func (r *Grammar) CompilePrune(_ string) string {
@@ -461,22 +1045,208 @@ func (r *Grammar) CompileRandomOrderForGorm() string {
 	return "RANDOM()"
 }
 
-func (r *Grammar) CompileRename(blueprint driver.Blueprint, command *driver.Command) string {
-	return fmt.Sprintf("alter table %s rename to %s", r.wrap.Table(blueprint.GetTableName()), r.wrap.Table(command.To))
+func (r *Grammar) CompileRangeAdjacent(column string, value any, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("%s -|- ?", r.wrap.Column(column)), isNot), []any{value}
 }
 
-func (r *Grammar) CompileRenameColumn(blueprint driver.Blueprint, command *driver.Command, _ []driver.Column) (string, error) {
-	return fmt.Sprintf("alter table %s rename column %s to %s",
-		r.wrap.Table(blueprint.GetTableName()),
-		r.wrap.Column(command.From),
-		r.wrap.Column(command.To),
-	), nil
+func (r *Grammar) CompileRangeContainedBy(column string, value any, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("%s <@ ?", r.wrap.Column(column)), isNot), []any{value}
 }
 
-func (r *Grammar) CompileRenameIndex(blueprint driver.Blueprint, command *driver.Command, _ []driver.Index) []string {
-	return []string{
-		fmt.Sprintf("alter index %s rename to %s", r.wrap.Column(command.From), r.wrap.Column(command.To)),
+// CompileRangeContains checks whether the range of a column contains a range, e.g. [2024-01-01,2024-02-01),
+// use CompileRangeContainsElement for a single value, which PostgreSQL would parse as a range otherwise.
+func (r *Grammar) CompileRangeContains(column string, value any, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("%s @> ?", r.wrap.Column(column)), isNot), []any{value}
+}
+
+// CompileRangeContainsElement checks whether the range of a column contains a value, which is cast to
+// the element type of the range, e.g. date for a daterange column.
+func (r *Grammar) CompileRangeContainsElement(column string, value any, elementType string, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("%s @> ?::%s", r.wrap.Column(column), elementType), isNot), []any{value}
+}
+
+func (r *Grammar) CompileRangeLower(column string) string {
+	return fmt.Sprintf("lower(%s)", r.wrap.Column(column))
+}
+
+func (r *Grammar) CompileRangeOverlaps(column string, value any, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("%s && ?", r.wrap.Column(column)), isNot), []any{value}
+}
+
+func (r *Grammar) CompileRangeUpper(column string) string {
+	return fmt.Sprintf("upper(%s)", r.wrap.Column(column))
+}
+
+func (r *Grammar) CompileRefreshMaterializedView(name string, concurrently bool) string {
+	sql := "refresh materialized view "
+	if concurrently {
+		sql += "concurrently "
+	}
+
+	return sql + r.EscapeNames([]string{name})[0]
+}
+
+func (r *Grammar) CompileRename(blueprint driver.Blueprint, command *driver.Command) string {
+	return r.lintStatement(blueprint, command, fmt.Sprintf("alter table %s rename to %s", r.wrap.Table(blueprint.GetTableName()), r.wrap.Table(command.To)))
+}
+
+func (r *Grammar) CompileRenameColumn(blueprint driver.Blueprint, command *driver.Command, _ []driver.Column) (string, error) {
+	if err := r.lint(blueprint, command); err != nil {
+		return "", err
 	}
+
+	return fmt.Sprintf("alter table %s rename column %s to %s",
+		r.wrap.Table(blueprint.GetTableName()),
+		r.wrap.Column(command.From),
+		r.wrap.Column(command.To),
+	), nil
+}
+
+func (r *Grammar) CompileRenameIndex(blueprint driver.Blueprint, command *driver.Command, _ []driver.Index) []string {
+	if sql := r.lintStatement(blueprint, command, ""); sql != "" {
+		return []string{sql}
+	}
+
+	return []string{
+		fmt.Sprintf("alter index %s rename to %s", r.wrap.Column(command.From), r.wrap.Column(command.To)),
+	}
+}
+
+func (r *Grammar) CompileRevoke(grant contracts.Grant) string {
+	sql := "revoke "
+	if grant.WithGrantOption {
+		sql += "grant option for "
+	}
+	sql += fmt.Sprintf("%s on %s from %s", r.getPrivileges(grant), r.getGrantObjects(grant), r.getRoles(grant.Roles))
+	if grant.Cascade {
+		sql += " cascade"
+	}
+
+	return sql
+}
+
+func (r *Grammar) CompileRevokeDefaultPrivileges(owner, schema string, grant contracts.Grant) string {
+	sql := "revoke "
+	if grant.WithGrantOption {
+		sql += "grant option for "
+	}
+	sql += fmt.Sprintf("%s on %s from %s", r.getPrivileges(grant), r.getDefaultPrivilegesObjectType(grant), r.getRoles(grant.Roles))
+	if grant.Cascade {
+		sql += " cascade"
+	}
+
+	return r.getAlterDefaultPrivileges(owner, schema) + sql
+}
+
+func (r *Grammar) CompileRenameSchema(from, to string) string {
+	return fmt.Sprintf("alter schema %s rename to %s", r.wrap.Value(from), r.wrap.Value(to))
+}
+
+// CompileResyncSequences moves the serial and identity sequences of the tables in the schema, or of the given table only,
+// past the maximum value of their columns, it's needed after the rows are inserted with explicit ids.
+func (r *Grammar) CompileResyncSequences(schema, table string) string {
+	condition := "n.nspname = " + r.quoteString(schema)
+	if table != "" {
+		condition += " and c.relname = " + r.quoteString(r.prefix+table)
+	}
+
+	return "do $$ declare s record; last_id bigint; min_id bigint; begin " +
+		"for s in select n.nspname, c.relname, a.attname, pg_get_serial_sequence(format('%I.%I', n.nspname, c.relname), a.attname) as seq_name " +
+		"from pg_class c join pg_namespace n on n.oid = c.relnamespace join pg_attribute a on a.attrelid = c.oid " +
+		"where c.relkind in ('r', 'p') and a.attnum > 0 and not a.attisdropped and " + condition + " " +
+		"and pg_get_serial_sequence(format('%I.%I', n.nspname, c.relname), a.attname) is not null loop " +
+		"execute format('select max(%I) from %I.%I', s.attname, s.nspname, s.relname) into last_id; " +
+		"select seqmin into min_id from pg_sequence where seqrelid = s.seq_name::regclass; " +
+		"if last_id is null or last_id < min_id then perform setval(s.seq_name::regclass, min_id, false); " +
+		"else perform setval(s.seq_name::regclass, last_id, true); end if; " +
+		"end loop; end $$"
+}
+
+func (r *Grammar) CompileSchemas() string {
+	return `select n.nspname as name, pg_get_userbyid(n.nspowner) as "authorization", obj_description(n.oid, 'pg_namespace') as comment ` +
+		"from pg_namespace n " +
+		`where n.nspname not like 'pg\_%' and n.nspname <> 'information_schema' ` +
+		"order by n.nspname"
+}
+
+func (r *Grammar) CompileSequences() string {
+	return "select s.sequencename as name, s.schemaname as schema, s.data_type as type, s.start_value as start, s.increment_by as increment, " +
+		"s.min_value, s.max_value, s.cache_size as cache, s.cycle, s.last_value, " +
+		"(select tc.relname || '.' || a.attname from pg_depend d " +
+		"join pg_class tc on tc.oid = d.refobjid " +
+		"join pg_attribute a on a.attrelid = d.refobjid and a.attnum = d.refobjsubid " +
+		"where d.objid = format('%I.%I', s.schemaname, s.sequencename)::regclass and d.classid = 'pg_class'::regclass " +
+		"and d.refclassid = 'pg_class'::regclass and d.deptype in ('a', 'i') limit 1) as owned_by, " +
+		"exists (select 1 from pg_depend d where d.objid = format('%I.%I', s.schemaname, s.sequencename)::regclass " +
+		"and d.classid = 'pg_class'::regclass and d.deptype = 'i') as identity " +
+		"from pg_sequences s where s.schemaname not in ('pg_catalog', 'information_schema') " +
+		"order by s.sequencename"
+}
+
+func (r *Grammar) CompileResetStorageParameters(table string, parameters []string) string {
+	return fmt.Sprintf("alter table %s reset (%s)", r.wrap.Table(table), strings.Join(parameters, ", "))
+}
+
+// CompileSetHnswEfSearch sets the size of the candidate list of the hnsw index scans for the current transaction,
+// a larger one improves the recall at the cost of speed. It must run in the same transaction as the query.
+func (r *Grammar) CompileSetHnswEfSearch(size int) string {
+	return fmt.Sprintf("set local hnsw.ef_search = %d", size)
+}
+
+// CompileSetIvfflatProbes sets the number of the lists that the ivfflat index scans search for the current transaction.
+func (r *Grammar) CompileSetIvfflatProbes(probes int) string {
+	return fmt.Sprintf("set local ivfflat.probes = %d", probes)
+}
+
+func (r *Grammar) CompileSetLogged(table string, logged bool) string {
+	if logged {
+		return fmt.Sprintf("alter table %s set logged", r.wrap.Table(table))
+	}
+
+	return fmt.Sprintf("alter table %s set unlogged", r.wrap.Table(table))
+}
+
+// CompileSetNotNull sets a column not null without scanning the table while holding an exclusive lock: it checks
+// the rows via a NOT VALID check constraint which is validated under a weaker lock, then sets the column not null,
+// which uses the validated constraint instead of a scan on PostgreSQL 12 or later, and drops the constraint.
+// The statements should be run one by one, each in its own transaction.
+func (r *Grammar) CompileSetNotNull(table, column string) []string {
+	_, name, _ := parseSchemaAndTable(table, "")
+	check := contracts.Check{
+		Name:       fmt.Sprintf("%s%s_%s_not_null", r.prefix, name, column),
+		Expression: r.wrap.Column(column) + " is not null",
+		NotValid:   true,
+	}
+
+	return []string{
+		r.CompileAddCheck(table, check),
+		r.CompileValidateConstraint(table, check.Name),
+		fmt.Sprintf("alter table %s alter column %s set not null", r.wrap.Table(table), r.wrap.Column(column)),
+		fmt.Sprintf("alter table %s drop constraint %s", r.wrap.Table(table), r.wrap.Value(check.Name)),
+	}
+}
+
+func (r *Grammar) CompileSetOwner(table, role string) string {
+	return fmt.Sprintf("alter table %s owner to %s", r.wrap.Table(table), r.getRoles([]string{role}))
+}
+
+func (r *Grammar) CompileSetSchema(table, schema string) string {
+	return fmt.Sprintf("alter table %s set schema %s", r.wrap.Table(table), r.wrap.Value(schema))
+}
+
+func (r *Grammar) CompileSetStorageParameters(table string, parameters map[string]any) string {
+	return fmt.Sprintf("alter table %s set (%s)", r.wrap.Table(table), r.getStorageParameters(parameters))
+}
+
+// CompileSetTrigramSimilarityThreshold sets the threshold of the % operator for the current transaction, so it must
+// run in the same transaction as the query and doesn't leak to the other queries of a pooled connection.
+func (r *Grammar) CompileSetTrigramSimilarityThreshold(threshold float64) (string, error) {
+	return r.compileSetTrigramThreshold("similarity", threshold)
+}
+
+// CompileSetTrigramWordSimilarityThreshold sets the threshold of the <% operator for the current transaction.
+func (r *Grammar) CompileSetTrigramWordSimilarityThreshold(threshold float64) (string, error) {
+	return r.compileSetTrigramThreshold("word_similarity", threshold)
 }
 
 func (r *Grammar) CompileSharedLock(builder sq.SelectBuilder, conditions *driver.Conditions) sq.SelectBuilder {
@@ -491,6 +1261,31 @@ func (r *Grammar) CompileSharedLockForGorm() clause.Expression {
 	return clause.Locking{Strength: "SHARE"}
 }
 
+func (r *Grammar) CompileSpatialContains(column string, geometry any, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("ST_Contains(%s, ST_GeomFromEWKT(?))", r.wrap.Column(column)), isNot), []any{geometry}
+}
+
+func (r *Grammar) CompileSpatialDWithin(column string, geometry any, distance float64, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("ST_DWithin(%s, ST_GeomFromEWKT(?), ?)", r.wrap.Column(column)), isNot), []any{geometry, distance}
+}
+
+func (r *Grammar) CompileSpatialIndex(blueprint driver.Blueprint, command *driver.Command) string {
+	spatial := *command
+	spatial.Algorithm = "gist"
+
+	return r.CompileIndex(blueprint, &spatial)
+}
+
+func (r *Grammar) CompileSpatialIntersects(column string, geometry any, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("ST_Intersects(%s, ST_GeomFromEWKT(?))", r.wrap.Column(column)), isNot), []any{geometry}
+}
+
+// CompileSpatialOrderByDistance orders by the distance to the geometry using the <-> operator, so the
+// nearest neighbours are found by a GiST index.
+func (r *Grammar) CompileSpatialOrderByDistance(column string, geometry any) (string, []any) {
+	return fmt.Sprintf("%s <-> ST_GeomFromEWKT(?)", r.wrap.Column(column)), []any{geometry}
+}
+
 func (r *Grammar) CompileTables(_ string) string {
 	return "select c.relname as name, n.nspname as schema, pg_total_relation_size(c.oid) as size, " +
 		"obj_description(c.oid, 'pg_class') as comment from pg_class c, pg_namespace n " +
@@ -498,16 +1293,108 @@ func (r *Grammar) CompileTables(_ string) string {
 		"order by c.relname"
 }
 
+func (r *Grammar) CompileTablePrivileges(schema, table string) (string, error) {
+	schema, table, err := parseSchemaAndTable(table, schema)
+	if err != nil {
+		return "", err
+	}
+
+	table = r.prefix + table
+
+	return fmt.Sprintf(
+		"select grantee, grantor, lower(privilege_type) as privilege, is_grantable = 'YES' as grantable "+
+			"from information_schema.role_table_grants "+
+			"where table_name = %s and table_schema = %s "+
+			"order by grantee, privilege_type",
+		r.wrap.Quote(table),
+		r.wrap.Quote(schema),
+	), nil
+}
+
 func (r *Grammar) CompileTableComment(blueprint driver.Blueprint, command *driver.Command) string {
-	return fmt.Sprintf("comment on table %s is '%s'",
+	return r.lintStatement(blueprint, command, fmt.Sprintf("comment on table %s is '%s'",
 		r.wrap.Table(blueprint.GetTableName()),
 		strings.ReplaceAll(command.Value, "'", "''"),
-	)
+	))
+}
+
+// CompileTrigramOrderByDistance orders by the trigram distance to the value using the <-> operator, so the
+// nearest matches are found by a gist_trgm_ops index.
+func (r *Grammar) CompileTrigramOrderByDistance(column, value string) (string, []any) {
+	return fmt.Sprintf("%s <-> ?", r.wrap.Column(column)), []any{value}
+}
+
+// CompileTrigramSimilar matches the columns that are more similar to the value than pg_trgm.similarity_threshold,
+// the % operator uses a gin_trgm_ops or gist_trgm_ops index.
+func (r *Grammar) CompileTrigramSimilar(column, value string, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("%s %% ?", r.wrap.Column(column)), isNot), []any{value}
+}
+
+func (r *Grammar) CompileTrigramSimilarity(column, value string) (string, []any) {
+	return fmt.Sprintf("similarity(%s, ?)", r.wrap.Column(column)), []any{value}
+}
+
+// CompileTrigramWordSimilar matches the columns that have a word more similar to the value than
+// pg_trgm.word_similarity_threshold, the <% operator uses a gin_trgm_ops or gist_trgm_ops index.
+func (r *Grammar) CompileTrigramWordSimilar(column, value string, isNot bool) (string, []any) {
+	return r.wrap.Not(fmt.Sprintf("? <%% %s", r.wrap.Column(column)), isNot), []any{value}
+}
+
+func (r *Grammar) CompileTrigramWordSimilarity(column, value string) (string, []any) {
+	return fmt.Sprintf("word_similarity(?, %s)", r.wrap.Column(column)), []any{value}
+}
+
+func (r *Grammar) CompileTriggers(schema, table string) (string, error) {
+	schema, table, err := parseSchemaAndTable(table, schema)
+	if err != nil {
+		return "", err
+	}
+
+	table = r.prefix + table
+
+	return fmt.Sprintf(
+		"select t.tgname as name, "+
+			"concat_ws(' or ', case when t.tgtype & 4 <> 0 then 'insert' end, "+
+			"case when t.tgtype & 16 <> 0 then 'update' || coalesce(' of ' || (select string_agg(quote_ident(a.attname), ', ' order by a.attnum) "+
+			"from pg_attribute a where a.attrelid = t.tgrelid and a.attnum = any(t.tgattr)), '') end, "+
+			"case when t.tgtype & 8 <> 0 then 'delete' end, case when t.tgtype & 32 <> 0 then 'truncate' end) as events, "+
+			"case when t.tgtype & 2 <> 0 then 'before' when t.tgtype & 64 <> 0 then 'instead of' else 'after' end as timing, "+
+			"case when t.tgtype & 1 <> 0 then 'row' else 'statement' end as for_each, "+
+			"(regexp_match(pg_get_triggerdef(t.oid), ' WHEN \\((.+)\\) EXECUTE (?:FUNCTION|PROCEDURE) '))[1] as \"when\", "+
+			"(regexp_match(pg_get_triggerdef(t.oid), ' (EXECUTE (?:FUNCTION|PROCEDURE) .*)$'))[1] as statement, "+
+			"pg_get_triggerdef(t.oid) as definition "+
+			"from pg_trigger t "+
+			"join pg_class c on c.oid = t.tgrelid "+
+			"join pg_namespace n on n.oid = c.relnamespace "+
+			"where not t.tgisinternal and c.relname = %s and n.nspname = %s "+
+			"order by t.tgname",
+		r.wrap.Quote(table),
+		r.wrap.Quote(schema),
+	), nil
 }
 
 func (r *Grammar) CompileTypes() string {
 	return `select t.typname as name, n.nspname as schema, t.typtype as type, t.typcategory as category, 
-		((t.typinput = 'array_in'::regproc and t.typoutput = 'array_out'::regproc) or t.typtype = 'm') as implicit 
+		((t.typinput = 'array_in'::regproc and t.typoutput = 'array_out'::regproc) or t.typtype = 'm') as implicit, 
+		case when t.typtype = 'd' then format_type(t.typbasetype, t.typtypmod) end as base_type, t.typnotnull as not_null, 
+		case when t.typtype = 'd' then t.typdefault end as "default", 
+		(select co.collname from pg_collation co join pg_type bt on bt.oid = t.typbasetype 
+		where co.oid = t.typcollation and t.typtype = 'd' and t.typcollation <> bt.typcollation) as collation, 
+		(select json_agg(json_build_object('name', a.attname, 'type', format_type(a.atttypid, a.atttypmod), 
+		'collation', (select co.collname from pg_collation co where co.oid = a.attcollation and a.attcollation <> at.typcollation)) order by a.attnum) 
+		from pg_attribute a join pg_type at on at.oid = a.atttypid 
+		where t.typtype = 'c' and a.attrelid = t.typrelid and a.attnum > 0 and not a.attisdropped) as attributes, 
+		(select json_build_object('subtype', format_type(rg.rngsubtype, null), 
+		'subtypeOpClass', case when not opc.opcdefault then quote_ident(opn.nspname) || '.' || quote_ident(opc.opcname) end, 
+		'collation', case when rg.rngcollation <> st.typcollation then (select co.collname from pg_collation co where co.oid = rg.rngcollation) end, 
+		'canonical', nullif(rg.rngcanonical::text, '-'), 'subtypeDiff', nullif(rg.rngsubdiff::text, '-'), 
+		'multirangeTypeName', (select mn.nspname || '.' || mt.typname from pg_type mt join pg_namespace mn on mn.oid = mt.typnamespace where mt.oid = rg.rngmultitypid)) 
+		from pg_range rg join pg_type st on st.oid = rg.rngsubtype 
+		join pg_opclass opc on opc.oid = rg.rngsubopc join pg_namespace opn on opn.oid = opc.opcnamespace 
+		where rg.rngtypid = t.oid) as "range", 
+		(select json_agg(json_build_object('name', con.conname, 'definition', pg_get_constraintdef(con.oid)) order by con.conname) 
+		from pg_constraint con where con.contypid = t.oid and con.contype = 'c') as constraints, 
+		(select json_agg(e.enumlabel order by e.enumsortorder) from pg_enum e where e.enumtypid = t.oid) as enum_labels 
 		from pg_type t 
 		join pg_namespace n on n.oid = t.typnamespace 
 		left join pg_class c on c.oid = t.typrelid 
@@ -519,35 +1406,91 @@ func (r *Grammar) CompileTypes() string {
 }
 
 func (r *Grammar) CompileUnique(blueprint driver.Blueprint, command *driver.Command) string {
+	table := blueprint.GetTableName()
 	sql := fmt.Sprintf("alter table %s add constraint %s unique (%s)",
-		r.wrap.Table(blueprint.GetTableName()),
+		r.wrap.Table(table),
 		r.wrap.Column(command.Index),
 		r.wrap.Columnize(command.Columns))
 
-	if command.Deferrable != nil {
-		if *command.Deferrable {
-			sql += " deferrable"
-		} else {
-			sql += " not deferrable"
-		}
-	}
-	if command.Deferrable != nil && command.InitiallyImmediate != nil {
-		if *command.InitiallyImmediate {
-			sql += " initially immediate"
-		} else {
-			sql += " initially deferred"
-		}
+	return r.lintStatement(blueprint, command,
+		r.guardConstraint(table, "conname = "+r.quoteString(command.Index), sql+r.getDeferrable(command.Deferrable, command.InitiallyImmediate)))
+}
+
+func (r *Grammar) CompileUpdateExtension(extension contracts.Extension) string {
+	sql := fmt.Sprintf("alter extension %s update", r.wrap.Value(extension.Name))
+	if extension.Version != "" {
+		sql += " to " + r.quoteString(extension.Version)
 	}
 
 	return sql
 }
 
+func (r *Grammar) CompileValidateConstraint(table, name string) string {
+	return fmt.Sprintf("alter table %s validate constraint %s", r.wrap.Table(table), r.wrap.Value(name))
+}
+
+// CompileValidateConstraints validates all the NOT VALID constraints of a table, e.g. the foreign keys that
+// are added NOT VALID via database.connections.%s.foreign_keys, which takes a lock that doesn't block writes.
+func (r *Grammar) CompileValidateConstraints(table string) string {
+	table = r.quoteString(r.wrap.Table(table))
+
+	return fmt.Sprintf("do $do$ declare c record; begin "+
+		"for c in select conname from pg_constraint where conrelid = %s::regclass and not convalidated order by conname loop "+
+		"execute format('alter table %%s validate constraint %%I', %s, c.conname); "+
+		"end loop; end $do$", table, table)
+}
+
+func (r *Grammar) CompileValidateDomainConstraint(domain, name string) string {
+	return fmt.Sprintf("alter domain %s validate constraint %s", r.EscapeNames([]string{domain})[0], r.wrap.Value(name))
+}
+
+// CompileVectorOrderByCosineDistance orders by the cosine distance to the vector, so the nearest neighbours
+// are found by an index with the vector_cosine_ops operator class.
+func (r *Grammar) CompileVectorOrderByCosineDistance(column string, vector any) (string, []any) {
+	return fmt.Sprintf("%s <=> ?", r.wrap.Column(column)), []any{vectorLiteral(vector)}
+}
+
+// CompileVectorOrderByInnerProduct orders by the negative inner product with the vector, so the largest inner
+// products come first and are found by an index with the vector_ip_ops operator class.
+func (r *Grammar) CompileVectorOrderByInnerProduct(column string, vector any) (string, []any) {
+	return fmt.Sprintf("%s <#> ?", r.wrap.Column(column)), []any{vectorLiteral(vector)}
+}
+
+// CompileVectorOrderByL2Distance orders by the Euclidean distance to the vector, so the nearest neighbours
+// are found by an index with the vector_l2_ops operator class.
+func (r *Grammar) CompileVectorOrderByL2Distance(column string, vector any) (string, []any) {
+	return fmt.Sprintf("%s <-> ?", r.wrap.Column(column)), []any{vectorLiteral(vector)}
+}
+
+// CompileServerVersionNum returns the version of the server as a number, e.g. 170002 for 17.2.
+func (r *Grammar) CompileServerVersionNum() string {
+	return "select current_setting('server_version_num')::int as value"
+}
+
 func (r *Grammar) CompileVersion() string {
 	return "SELECT current_setting('server_version') AS value;"
 }
 
+// CompileViews returns the views and the materialized views except the ones of the extensions, the dependencies
+// are a JSON array of the schemas and the names of the views that a view selects from, and the indexes are a
+// JSON array of the definitions of the indexes of a materialized view.
 func (r *Grammar) CompileViews(database string) string {
-	return "select viewname as name, schemaname as schema, definition from pg_views where schemaname not in ('pg_catalog', 'information_schema') order by viewname"
+	return "select v.name, v.schema, v.definition, v.materialized, " +
+		"(select json_agg(pg_get_indexdef(i.indexrelid) order by ic.relname) from pg_index i " +
+		"join pg_class ic on ic.oid = i.indexrelid where i.indrelid = c.oid) as indexes, " +
+		"(select json_agg(json_build_object('schema', dn.nspname, 'name', dc.relname) order by dn.nspname, dc.relname) " +
+		"from pg_rewrite rw " +
+		"join pg_depend d on d.classid = 'pg_rewrite'::regclass and d.objid = rw.oid " +
+		"join pg_class dc on dc.oid = d.refobjid " +
+		"join pg_namespace dn on dn.oid = dc.relnamespace " +
+		"where rw.ev_class = c.oid and dc.relkind in ('v', 'm') and dc.oid <> c.oid) as dependencies " +
+		"from (select viewname as name, schemaname as schema, definition, false as materialized from pg_views " +
+		"union all select matviewname as name, schemaname as schema, definition, true as materialized from pg_matviews) v " +
+		"join pg_namespace n on n.nspname = v.schema " +
+		"join pg_class c on c.relnamespace = n.oid and c.relname = v.name " +
+		"where v.schema not in ('pg_catalog', 'information_schema') " +
+		"and not exists (select 1 from pg_depend d where d.objid = c.oid and d.deptype = 'e') " +
+		"order by v.name"
 }
 
 func (r *Grammar) EscapeNames(names []string) []string {
@@ -569,18 +1512,38 @@ func (r *Grammar) GetAttributeCommands() []string {
 	return r.attributeCommands
 }
 
+func (r *Grammar) ModifyCollation(_ driver.Blueprint, column driver.ColumnDefinition) string {
+	// The collation of a changed column is set along with its type, see CompileChange.
+	if collation := r.getAttributes(column).collation; collation != "" && !column.IsChange() {
+		return " collate " + r.wrap.Value(collation)
+	}
+
+	return ""
+}
+
+func (r *Grammar) ModifyCompression(_ driver.Blueprint, column driver.ColumnDefinition) string {
+	if compression := r.getAttributes(column).compression; compression != "" {
+		if column.IsChange() {
+			return " set compression " + compression
+		}
+		return " compression " + compression
+	}
+
+	return ""
+}
+
 func (r *Grammar) ModifyDefault(_ driver.Blueprint, column driver.ColumnDefinition) string {
 	if column.IsChange() {
 		if column.GetAutoIncrement() || column.IsSetGeneratedAs() {
 			return ""
 		}
 		if column.GetDefault() != nil {
-			return fmt.Sprintf(" set default %s", schema.ColumnDefaultValue(column.GetDefault()))
+			return fmt.Sprintf(" set default %s", r.getDefaultValue(column.GetDefault()))
 		}
 		return " drop default"
 	}
 	if column.GetDefault() != nil {
-		return fmt.Sprintf(" default %s", schema.ColumnDefaultValue(column.GetDefault()))
+		return fmt.Sprintf(" default %s", r.getDefaultValue(column.GetDefault()))
 	}
 
 	return ""
@@ -642,6 +1605,17 @@ func (r *Grammar) ModifyIncrement(blueprint driver.Blueprint, column driver.Colu
 	return ""
 }
 
+func (r *Grammar) ModifyStorage(_ driver.Blueprint, column driver.ColumnDefinition) string {
+	if storage := r.getAttributes(column).storage; storage != "" {
+		if column.IsChange() {
+			return " set storage " + storage
+		}
+		return " storage " + storage
+	}
+
+	return ""
+}
+
 func (r *Grammar) TypeBigInteger(column driver.ColumnDefinition) string {
 	if column.GetAutoIncrement() && !column.IsChange() && !column.IsSetGeneratedAs() {
 		return "bigserial"
@@ -650,6 +1624,19 @@ func (r *Grammar) TypeBigInteger(column driver.ColumnDefinition) string {
 	return "bigint"
 }
 
+func (r *Grammar) TypeBinary(column driver.ColumnDefinition) string {
+	return "bytea"
+}
+
+func (r *Grammar) TypeBit(column driver.ColumnDefinition) string {
+	length := column.GetLength()
+	if length > 0 {
+		return fmt.Sprintf("bit(%d)", length)
+	}
+
+	return "bit"
+}
+
 func (r *Grammar) TypeBoolean(column driver.ColumnDefinition) string {
 	return "boolean"
 }
@@ -663,10 +1650,22 @@ func (r *Grammar) TypeChar(column driver.ColumnDefinition) string {
 	return "char"
 }
 
+func (r *Grammar) TypeCidr(column driver.ColumnDefinition) string {
+	return "cidr"
+}
+
 func (r *Grammar) TypeDate(column driver.ColumnDefinition) string {
 	return "date"
 }
 
+func (r *Grammar) TypeDateMultiRange(column driver.ColumnDefinition) string {
+	return "datemultirange"
+}
+
+func (r *Grammar) TypeDateRange(column driver.ColumnDefinition) string {
+	return "daterange"
+}
+
 func (r *Grammar) TypeDateTime(column driver.ColumnDefinition) string {
 	return r.TypeTimestamp(column)
 }
@@ -696,6 +1695,38 @@ func (r *Grammar) TypeFloat(column driver.ColumnDefinition) string {
 	return "float"
 }
 
+func (r *Grammar) TypeGeography(column driver.ColumnDefinition) string {
+	return "geography"
+}
+
+func (r *Grammar) TypeGeometry(column driver.ColumnDefinition) string {
+	return "geometry"
+}
+
+func (r *Grammar) TypeHalfVector(column driver.ColumnDefinition) string {
+	return vectorType("halfvec", column.GetLength())
+}
+
+func (r *Grammar) TypeInet(column driver.ColumnDefinition) string {
+	return "inet"
+}
+
+func (r *Grammar) TypeInt4MultiRange(column driver.ColumnDefinition) string {
+	return "int4multirange"
+}
+
+func (r *Grammar) TypeInt4Range(column driver.ColumnDefinition) string {
+	return "int4range"
+}
+
+func (r *Grammar) TypeInt8MultiRange(column driver.ColumnDefinition) string {
+	return "int8multirange"
+}
+
+func (r *Grammar) TypeInt8Range(column driver.ColumnDefinition) string {
+	return "int8range"
+}
+
 func (r *Grammar) TypeInteger(column driver.ColumnDefinition) string {
 	if column.GetAutoIncrement() && !column.IsChange() && !column.IsSetGeneratedAs() {
 		return "serial"
@@ -704,6 +1735,25 @@ func (r *Grammar) TypeInteger(column driver.ColumnDefinition) string {
 	return "integer"
 }
 
+// TypeInterval returns an interval with the fields and the precision of the column type, see Interval.
+func (r *Grammar) TypeInterval(column driver.ColumnDefinition) string {
+	ttype := "interval"
+	if column, ok := column.(*typedColumn); ok {
+		if column.fields != "" {
+			ttype += " " + column.fields
+		}
+		// A precision of the column type is kept even when it's 0, which drops the fractional seconds.
+		if len(column.modifiers) > 0 {
+			return fmt.Sprintf("%s(%d)", ttype, column.modifiers[0])
+		}
+	}
+	if precision := column.GetPrecision(); precision > 0 {
+		return fmt.Sprintf("%s(%d)", ttype, precision)
+	}
+
+	return ttype
+}
+
 func (r *Grammar) TypeJson(column driver.ColumnDefinition) string {
 	return "json"
 }
@@ -716,6 +1766,14 @@ func (r *Grammar) TypeLongText(column driver.ColumnDefinition) string {
 	return "text"
 }
 
+func (r *Grammar) TypeMacAddress(column driver.ColumnDefinition) string {
+	return "macaddr"
+}
+
+func (r *Grammar) TypeMacAddress8(column driver.ColumnDefinition) string {
+	return "macaddr8"
+}
+
 func (r *Grammar) TypeMediumInteger(column driver.ColumnDefinition) string {
 	return r.TypeInteger(column)
 }
@@ -724,6 +1782,18 @@ func (r *Grammar) TypeMediumText(column driver.ColumnDefinition) string {
 	return "text"
 }
 
+func (r *Grammar) TypeMoney(column driver.ColumnDefinition) string {
+	return "money"
+}
+
+func (r *Grammar) TypeNumMultiRange(column driver.ColumnDefinition) string {
+	return "nummultirange"
+}
+
+func (r *Grammar) TypeNumRange(column driver.ColumnDefinition) string {
+	return "numrange"
+}
+
 func (r *Grammar) TypeSmallInteger(column driver.ColumnDefinition) string {
 	if column.GetAutoIncrement() && !column.IsChange() && !column.IsSetGeneratedAs() {
 		return "smallserial"
@@ -732,6 +1802,10 @@ func (r *Grammar) TypeSmallInteger(column driver.ColumnDefinition) string {
 	return "smallint"
 }
 
+func (r *Grammar) TypeSparseVector(column driver.ColumnDefinition) string {
+	return vectorType("sparsevec", column.GetLength())
+}
+
 func (r *Grammar) TypeString(column driver.ColumnDefinition) string {
 	length := column.GetLength()
 	if length > 0 {
@@ -777,10 +1851,207 @@ func (r *Grammar) TypeTinyText(column driver.ColumnDefinition) string {
 	return "varchar(255)"
 }
 
+func (r *Grammar) TypeTsMultiRange(column driver.ColumnDefinition) string {
+	return "tsmultirange"
+}
+
+func (r *Grammar) TypeTsQuery(column driver.ColumnDefinition) string {
+	return "tsquery"
+}
+
+func (r *Grammar) TypeTsRange(column driver.ColumnDefinition) string {
+	return "tsrange"
+}
+
+func (r *Grammar) TypeTsTzMultiRange(column driver.ColumnDefinition) string {
+	return "tstzmultirange"
+}
+
+func (r *Grammar) TypeTsTzRange(column driver.ColumnDefinition) string {
+	return "tstzrange"
+}
+
+func (r *Grammar) TypeTsVector(column driver.ColumnDefinition) string {
+	return "tsvector"
+}
+
 func (r *Grammar) TypeUuid(column driver.ColumnDefinition) string {
 	return "uuid"
 }
 
+func (r *Grammar) TypeVarBit(column driver.ColumnDefinition) string {
+	length := column.GetLength()
+	if length > 0 {
+		return fmt.Sprintf("varbit(%d)", length)
+	}
+
+	return "varbit"
+}
+
+func (r *Grammar) TypeVector(column driver.ColumnDefinition) string {
+	return vectorType("vector", column.GetLength())
+}
+
+func (r *Grammar) TypeXml(column driver.ColumnDefinition) string {
+	return "xml"
+}
+
+func (r *Grammar) compileCreate(table string, columns []string, options contracts.TableOptions) string {
+	sql := "create "
+	if options.Temporary {
+		sql += "temporary "
+	} else if options.Unlogged {
+		sql += "unlogged "
+	}
+	sql += "table "
+	if options.IfNotExists || r.idempotent {
+		sql += "if not exists "
+	}
+	sql += fmt.Sprintf("%s (%s)", r.wrap.Table(table), strings.Join(columns, ", "))
+	if len(options.Inherits) > 0 {
+		sql += fmt.Sprintf(" inherits (%s)", strings.Join(collect.Map(options.Inherits, func(table string, _ int) string {
+			return r.wrap.Table(table)
+		}), ", "))
+	}
+	if len(options.With) > 0 {
+		sql += fmt.Sprintf(" with (%s)", r.getStorageParameters(options.With))
+	}
+	if options.Temporary && options.OnCommit != "" {
+		sql += " on commit " + options.OnCommit
+	}
+	if options.Tablespace != "" {
+		sql += " tablespace " + r.wrap.Value(options.Tablespace)
+	}
+
+	return sql
+}
+
+// compileSetTrigramThreshold sets a pg_trgm threshold, it's formatted without an exponent, which PostgreSQL
+// doesn't accept for the setting, e.g. 0.00001 instead of 1e-05.
+func (r *Grammar) compileSetTrigramThreshold(name string, threshold float64) (string, error) {
+	value := strconv.FormatFloat(threshold, 'f', -1, 64)
+	if !(threshold >= 0 && threshold <= 1) {
+		return "", InvalidThreshold.Args(name, value)
+	}
+
+	return fmt.Sprintf("set local pg_trgm.%s_threshold = %s", name, value), nil
+}
+
+// compileType recreates an introspected enum, range, domain or composite type, it returns an empty string for
+// the other types, which can't be recreated from their metadata.
+func (r *Grammar) compileType(t Type) string {
+	name := t.Schema + "." + t.Name
+	switch t.Type.Type {
+	case "enum":
+		return fmt.Sprintf("create type %s as enum (%s)", r.EscapeNames([]string{name})[0],
+			strings.Join(collect.Map(t.Labels, func(label string, _ int) string {
+				return r.quoteString(label)
+			}), ", "))
+	case "range":
+		rangeType := t.Range
+		rangeType.Name = name
+
+		return r.CompileCreateRangeType(rangeType)
+	case "domain":
+		domain := contracts.Domain{
+			Name:      name,
+			Type:      t.BaseType,
+			Collation: t.Collation,
+			NotNull:   t.NotNull,
+			Checks:    t.Checks,
+		}
+		if t.Default != "" {
+			domain.Default = schema.Expression(t.Default)
+		}
+
+		return r.CompileCreateDomain(domain)
+	case "composite":
+		return fmt.Sprintf("create type %s as (%s)", r.EscapeNames([]string{name})[0],
+			strings.Join(collect.Map(t.Attributes, func(attribute TypeAttribute, _ int) string {
+				sql := fmt.Sprintf("%s %s", r.wrap.Column(attribute.Name), attribute.Type)
+				if attribute.Collation != "" {
+					sql += " collate " + r.wrap.Value(attribute.Collation)
+				}
+
+				return sql
+			}), ", "))
+	}
+
+	return ""
+}
+
+// compileTrigger recreates an introspected trigger via its definition, the table that is already wrapped is
+// only used by the triggers of a snapshot without definitions.
+func (r *Grammar) compileTrigger(table string, trigger Trigger) string {
+	if trigger.Definition != "" {
+		return trigger.Definition
+	}
+
+	sql := fmt.Sprintf("create trigger %s %s %s on %s for each %s",
+		r.wrap.Value(trigger.Name), trigger.Timing, strings.Join(trigger.Events, " or "), table, trigger.ForEach)
+	if trigger.When != "" {
+		sql += fmt.Sprintf(" when (%s)", trigger.When)
+	}
+
+	return sql + " " + trigger.Statement
+}
+
+func (r *Grammar) getAlterDefaultPrivileges(owner, schema string) string {
+	sql := "alter default privileges "
+	if owner != "" {
+		sql += "for role " + r.wrap.Value(owner) + " "
+	}
+	if schema != "" {
+		sql += "in schema " + r.wrap.Value(schema) + " "
+	}
+
+	return sql
+}
+
+func (r *Grammar) getAttributes(column driver.ColumnDefinition) columnAttributes {
+	if column, ok := column.(*attributedColumn); ok {
+		return column.attributes
+	}
+
+	return columnAttributes{}
+}
+
+func (r *Grammar) getCheck(check contracts.Check) string {
+	sql := fmt.Sprintf("check (%s)", check.Expression)
+	if check.Name != "" {
+		sql = fmt.Sprintf("constraint %s %s", r.wrap.Value(check.Name), sql)
+	}
+
+	return sql
+}
+
+// getColumnDefinition returns the definition of an introspected column, e.g. "name" character varying(255) not null.
+func (r *Grammar) getColumnDefinition(column Column) string {
+	sql := fmt.Sprintf("%s %s", r.wrap.Column(column.Name), column.Type)
+	if column.Storage != "" {
+		sql += " storage " + column.Storage
+	}
+	if column.Compression != "" {
+		sql += " compression " + column.Compression
+	}
+	if column.Collation != "" {
+		sql += " collate " + r.wrap.Value(column.Collation)
+	}
+	if column.Generation != "" {
+		sql += fmt.Sprintf(" generated always as (%s) %s", column.Generation, column.Generated)
+	} else if column.Default != "" {
+		sql += " default " + column.Default
+	}
+	if column.Identity != "" {
+		sql += fmt.Sprintf(" generated %s as identity", column.Identity)
+	}
+	if !column.Nullable {
+		sql += " not null"
+	}
+
+	return sql
+}
+
 func (r *Grammar) getColumns(blueprint driver.Blueprint) []string {
 	var columns []string
 	for _, column := range blueprint.GetAddedColumns() {
@@ -791,7 +2062,9 @@ func (r *Grammar) getColumns(blueprint driver.Blueprint) []string {
 }
 
 func (r *Grammar) getColumn(blueprint driver.Blueprint, column driver.ColumnDefinition) string {
-	sql := fmt.Sprintf("%s %s", r.wrap.Column(column.GetName()), schema.ColumnType(r, column))
+	ttype, attributes := r.getType(column)
+	column = &attributedColumn{ColumnDefinition: column, attributes: attributes}
+	sql := fmt.Sprintf("%s %s", r.wrap.Column(column.GetName()), ttype)
 
 	for _, modifier := range r.modifiers {
 		sql += modifier(blueprint, column)
@@ -800,6 +2073,378 @@ func (r *Grammar) getColumn(blueprint driver.Blueprint, column driver.ColumnDefi
 	return sql
 }
 
+// getDeferrable returns the DEFERRABLE and INITIALLY clauses of a constraint, the initial mode is only set
+// when the deferrability is set, PostgreSQL rejects it otherwise.
+func (r *Grammar) getDeferrable(deferrable, initiallyImmediate *bool) string {
+	var sql string
+	if deferrable != nil {
+		if *deferrable {
+			sql += " deferrable"
+		} else {
+			sql += " not deferrable"
+		}
+	}
+	if deferrable != nil && initiallyImmediate != nil {
+		if *initiallyImmediate {
+			sql += " initially immediate"
+		} else {
+			sql += " initially deferred"
+		}
+	}
+
+	return sql
+}
+
+// getDefaultPrivilegesObjectType returns the plural object type that ALTER DEFAULT PRIVILEGES expects, e.g. tables.
+func (r *Grammar) getDefaultPrivilegesObjectType(grant contracts.Grant) string {
+	return strings.TrimSuffix(strings.ToLower(grant.ObjectType), "s") + "s"
+}
+
+func (r *Grammar) getGrantObjects(grant contracts.Grant) string {
+	objectType := strings.ToLower(grant.ObjectType)
+
+	var objects []string
+	switch {
+	case strings.HasPrefix(objectType, "all "):
+		objects = collect.Map(grant.Objects, func(object string, _ int) string {
+			return r.wrap.Value(object)
+		})
+	case objectType == "table":
+		objects = collect.Map(grant.Objects, func(object string, _ int) string {
+			return r.wrap.Table(object)
+		})
+	case objectType == "schema":
+		objects = collect.Map(grant.Objects, func(object string, _ int) string {
+			return r.wrap.Value(object)
+		})
+	default:
+		objects = collect.Map(grant.Objects, func(object string, _ int) string {
+			name, arguments, ok := strings.Cut(object, "(")
+			if ok {
+				return r.EscapeNames([]string{name})[0] + "(" + arguments
+			}
+
+			return r.EscapeNames([]string{name})[0]
+		})
+	}
+
+	return objectType + " " + strings.Join(objects, ", ")
+}
+
+func (r *Grammar) getDefaultValue(def any) string {
+	value := reflect.ValueOf(def)
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Type().Elem().Kind() != reflect.Uint8 {
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(arrayLiteral(value), "'", "''"))
+	}
+
+	return schema.ColumnDefaultValue(def)
+}
+
+func (r *Grammar) getPrivileges(grant contracts.Grant) string {
+	if len(grant.Privileges) == 0 {
+		return "all privileges"
+	}
+
+	return strings.Join(grant.Privileges, ", ")
+}
+
+func (r *Grammar) ifExists() string {
+	if r.idempotent {
+		return "if exists "
+	}
+
+	return ""
+}
+
+func (r *Grammar) ifNotExists() string {
+	if r.idempotent {
+		return "if not exists "
+	}
+
+	return ""
+}
+
+// lint returns the error of the error-level issues of a blueprint when the lint levels are set for the connection,
+// see Linter. All the commands of the blueprint are linted when its first command is compiled, which logs the
+// warnings, so the first statement of the blueprint fails and none of its changes are applied.
+func (r *Grammar) lint(blueprint driver.Blueprint, command *driver.Command) error {
+	if r.linter == nil {
+		return nil
+	}
+
+	commands := blueprint.GetCommands()
+	first := slices.IndexFunc(commands, func(command *driver.Command) bool { return !command.ShouldBeSkipped })
+	if first < 0 || commands[first] != command {
+		return nil
+	}
+
+	issues, err := r.linter.Lint(blueprint)
+	r.linter.warn(issues)
+
+	return err
+}
+
+// lintStatement replaces the statement of the first command of a blueprint with one that raises the lint error
+// of the blueprint, so the migration fails before any of its statements blocks the table.
+func (r *Grammar) lintStatement(blueprint driver.Blueprint, command *driver.Command, sql string) string {
+	if err := r.lint(blueprint, command); err != nil {
+		return fmt.Sprintf("do $do$ begin raise exception '%%', %s; end $do$", r.quoteString(err.Error()))
+	}
+
+	return sql
+}
+
+// quoteString quotes a value as a string literal, single quotes in the value are doubled.
+func (r *Grammar) quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func (r *Grammar) getPolicyOptions(policy contracts.Policy) string {
+	var sql string
+	if len(policy.Roles) > 0 {
+		sql += " to " + r.getRoles(policy.Roles)
+	}
+	if policy.Using != "" {
+		sql += fmt.Sprintf(" using (%s)", policy.Using)
+	}
+	if policy.WithCheck != "" {
+		sql += fmt.Sprintf(" with check (%s)", policy.WithCheck)
+	}
+
+	return sql
+}
+
+func (r *Grammar) getRoleOptions(role contracts.Role) []string {
+	var options []string
+	for _, option := range []struct {
+		value *bool
+		name  string
+	}{
+		{role.Superuser, "superuser"},
+		{role.CreateDB, "createdb"},
+		{role.CreateRole, "createrole"},
+		{role.Inherit, "inherit"},
+		{role.Login, "login"},
+		{role.Replication, "replication"},
+		{role.BypassRLS, "bypassrls"},
+	} {
+		if option.value == nil {
+			continue
+		}
+		if *option.value {
+			options = append(options, option.name)
+		} else {
+			options = append(options, "no"+option.name)
+		}
+	}
+	if role.ConnectionLimit != nil {
+		options = append(options, fmt.Sprintf("connection limit %d", *role.ConnectionLimit))
+	}
+	if role.Password != nil {
+		if *role.Password == "" {
+			options = append(options, "password null")
+		} else {
+			options = append(options, "password "+r.quoteString(*role.Password))
+		}
+	}
+	if role.ValidUntil != "" {
+		options = append(options, "valid until "+r.quoteString(role.ValidUntil))
+	}
+
+	return options
+}
+
+// getRoles wraps the names of roles, leaving the keywords that refer to special roles as they are.
+func (r *Grammar) getRoles(roles []string) string {
+	return strings.Join(collect.Map(roles, func(role string, _ int) string {
+		if slices.Contains([]string{"public", "current_role", "current_user", "session_user"}, strings.ToLower(role)) {
+			return strings.ToLower(role)
+		}
+
+		return r.wrap.Value(role)
+	}), ", ")
+}
+
+func (r *Grammar) getSequenceOptions(sequence contracts.Sequence) []string {
+	var options []string
+	if sequence.As != "" {
+		options = append(options, "as "+sequence.As)
+	}
+	if sequence.Increment != nil {
+		options = append(options, fmt.Sprintf("increment by %d", *sequence.Increment))
+	}
+	if sequence.MinValue != nil {
+		options = append(options, fmt.Sprintf("minvalue %d", *sequence.MinValue))
+	}
+	if sequence.MaxValue != nil {
+		options = append(options, fmt.Sprintf("maxvalue %d", *sequence.MaxValue))
+	}
+	if sequence.Start != nil {
+		options = append(options, fmt.Sprintf("start with %d", *sequence.Start))
+	}
+	if sequence.Restart != nil {
+		options = append(options, fmt.Sprintf("restart with %d", *sequence.Restart))
+	}
+	if sequence.Cache != nil {
+		options = append(options, fmt.Sprintf("cache %d", *sequence.Cache))
+	}
+	if sequence.Cycle != nil {
+		if *sequence.Cycle {
+			options = append(options, "cycle")
+		} else {
+			options = append(options, "no cycle")
+		}
+	}
+	if sequence.OwnedBy == "none" {
+		options = append(options, "owned by none")
+	} else if sequence.OwnedBy != "" {
+		options = append(options, "owned by "+r.wrap.Column(sequence.OwnedBy))
+	}
+
+	return options
+}
+
+func (r *Grammar) getStorageParameters(parameters map[string]any) string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return strings.Join(collect.Map(names, func(name string, _ int) string {
+		return fmt.Sprintf("%s = %v", name, parameters[name])
+	}), ", ")
+}
+
+func (r *Grammar) getTsLanguage(search contracts.FullTextSearch) string {
+	if search.Language != "" {
+		return search.Language
+	}
+
+	return "english"
+}
+
+func (r *Grammar) getTsQuery(search contracts.FullTextSearch) string {
+	function := "websearch_to_tsquery"
+	switch search.Mode {
+	case "plain":
+		function = "plainto_tsquery"
+	case "phrase":
+		function = "phraseto_tsquery"
+	}
+
+	return fmt.Sprintf("%s(%s, ?)", function, r.wrap.Quote(r.getTsLanguage(search)))
+}
+
+func (r *Grammar) getTsVector(language string, columns []string, weights map[string]string) string {
+	return strings.Join(collect.Map(columns, func(column string, _ int) string {
+		vector := fmt.Sprintf("to_tsvector(%s, %s)", r.wrap.Quote(language), r.wrap.Column(column))
+		if weights != nil {
+			weight := weights[column]
+			if weight == "" {
+				weight = "D"
+			}
+			vector = fmt.Sprintf("setweight(%s, %s)", vector, r.quoteString(strings.ToUpper(weight)))
+		}
+
+		return vector
+	}), " || ")
+}
+
+// getType returns the type of a column and the attributes that follow the type, e.g. the collation.
+func (r *Grammar) getType(column driver.ColumnDefinition) (string, columnAttributes) {
+	ttype, attributes := parseColumnAttributes(column.GetType())
+	ttype, dimensions := parseArrayType(ttype)
+	typed := &typedColumn{
+		ColumnDefinition: column,
+		ttype:            ttype,
+		array:            dimensions > 0,
+	}
+	if name, modifiers, ok := parseTypeModifiers(ttype); ok && r.hasType(name) {
+		typed.ttype = name
+		typed.modifiers = modifiers
+	} else if fields, modifiers, ok := parseIntervalFields(ttype); ok {
+		typed.ttype = "interval"
+		typed.modifiers = modifiers
+		typed.fields = fields
+	}
+
+	return schema.ColumnType(r, typed) + strings.Repeat(arraySuffix, dimensions), attributes
+}
+
+// getUsing returns the USING expression of the conversions that PostgreSQL doesn't do by itself,
+// or does in a way that loses meaning, e.g. varchar to integer or timestamp to timestamptz.
+func (r *Grammar) getUsing(column, from, to string) string {
+	fromName, _, fromDimensions := parseSQLType(from)
+	toName, _, toDimensions := parseSQLType(to)
+	if fromName == toName || fromDimensions > 0 || toDimensions > 0 {
+		return ""
+	}
+
+	stringTypes := []string{"bpchar", "text", "varchar"}
+	integerTypes := []string{"int2", "int4", "int8"}
+	column = r.wrap.Column(column)
+
+	switch {
+	case slices.Contains(stringTypes, fromName) && !slices.Contains(stringTypes, toName):
+		return fmt.Sprintf("%s::%s", column, to)
+	case fromName == "timestamp" && toName == "timestamptz", fromName == "timestamptz" && toName == "timestamp":
+		return fmt.Sprintf("%s at time zone 'UTC'", column)
+	case slices.Contains(integerTypes, fromName) && toName == "bool":
+		return fmt.Sprintf("%s <> 0", column)
+	case fromName == "bool" && (slices.Contains(integerTypes, toName) || toName == "numeric"):
+		return fmt.Sprintf("case when %s then 1 else 0 end", column)
+	}
+
+	return ""
+}
+
+// guardConstraint adds a constraint only when the table doesn't have it yet in the idempotent mode,
+// the condition filters the constraints of the table in pg_constraint.
+func (r *Grammar) guardConstraint(table, condition, sql string) string {
+	if !r.idempotent {
+		return sql
+	}
+
+	return fmt.Sprintf("do $do$ begin if not exists (select 1 from pg_constraint where conrelid = %s::regclass and %s) then %s; end if; end $do$",
+		r.quoteString(r.wrap.Table(table)), condition, sql)
+}
+
+func (r *Grammar) hasType(ttype string) bool {
+	if ttype == "" {
+		return false
+	}
+
+	return reflect.ValueOf(r).MethodByName("Type" + strings.ToUpper(ttype[:1]) + ttype[1:]).IsValid()
+}
+
+// arrayLiteral converts a Go slice to a PostgreSQL array literal, e.g. []string{"a", "b"} to {"a","b"}.
+func arrayLiteral(value reflect.Value) string {
+	elements := make([]string, value.Len())
+	for i := range elements {
+		element := value.Index(i)
+		for element.Kind() == reflect.Interface || element.Kind() == reflect.Pointer {
+			if element.IsNil() {
+				break
+			}
+			element = element.Elem()
+		}
+
+		switch element.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			elements[i] = "NULL"
+		case reflect.Slice, reflect.Array:
+			elements[i] = arrayLiteral(element)
+		case reflect.String:
+			elements[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(element.String()) + `"`
+		default:
+			elements[i] = cast.ToString(element.Interface())
+		}
+	}
+
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
 func parseSchemaAndTable(reference, defaultSchema string) (string, string, error) {
 	if reference == "" {
 		return "", "", errors.SchemaEmptyReferenceString
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"math"
 	"testing"
 
 	contractsdriver "github.com/goravel/framework/contracts/database/driver"
@@ -13,6 +14,8 @@ import (
 	"github.com/goravel/framework/support/convert"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/goravel/postgres/contracts"
 )
 
 type GrammarSuite struct {
@@ -49,6 +52,158 @@ func (s *GrammarSuite) TestCompileAdd() {
 	s.Equal(`alter table "goravel_users" add column "name" varchar(1) default 'goravel' not null`, sql)
 }
 
+func (s *GrammarSuite) TestCompileAddDomainConstraint() {
+	s.Equal(`alter domain "public"."positive" add constraint "positive_check" check (VALUE > 0)`, s.grammar.CompileAddDomainConstraint("public.positive", contracts.Check{
+		Name:       "positive_check",
+		Expression: "VALUE > 0",
+	}))
+	s.Equal(`alter domain "positive" add check (VALUE < 100) not valid`, s.grammar.CompileAddDomainConstraint("positive", contracts.Check{
+		Expression: "VALUE < 100",
+		NotValid:   true,
+	}))
+}
+
+func (s *GrammarSuite) TestCompileAddCheck() {
+	s.Equal(`alter table "goravel_orders" add constraint "orders_total_check" check (total >= 0)`, s.grammar.CompileAddCheck("orders", contracts.Check{
+		Name:       "orders_total_check",
+		Expression: "total >= 0",
+	}))
+	s.Equal(`alter table "goravel_orders" add check (total >= 0) not valid`, s.grammar.CompileAddCheck("orders", contracts.Check{
+		Expression: "total >= 0",
+		NotValid:   true,
+	}))
+}
+
+func (s *GrammarSuite) TestCompileAddForeignKey() {
+	s.Equal(`alter table "goravel_orders" add constraint "orders_user_id_foreign" foreign key ("user_id") references "goravel_users" ("id") not valid`,
+		s.grammar.CompileAddForeignKey(contracts.ForeignKey{
+			Table:      "orders",
+			Name:       "orders_user_id_foreign",
+			Columns:    []string{"user_id"},
+			On:         "users",
+			References: []string{"id"},
+			NotValid:   true,
+		}))
+	s.Equal(`alter table "goravel_orders" add constraint "orders_tenant_user_foreign" foreign key ("tenant_id", "user_id") references "goravel_users" ("tenant_id", "id") `+
+		`match full on delete cascade deferrable initially deferred`,
+		s.grammar.CompileAddForeignKey(contracts.ForeignKey{
+			Table:              "orders",
+			Name:               "orders_tenant_user_foreign",
+			Columns:            []string{"tenant_id", "user_id"},
+			On:                 "users",
+			References:         []string{"tenant_id", "id"},
+			OnDelete:           "cascade",
+			Match:              "full",
+			Deferrable:         convert.Pointer(true),
+			InitiallyImmediate: convert.Pointer(false),
+		}))
+}
+
+func (s *GrammarSuite) TestCompileAlterColumnType() {
+	tests := []struct {
+		name          string
+		change        contracts.ColumnTypeChange
+		expectSql     string
+		expectRewrite bool
+	}{
+		{
+			name:          "unknown current type",
+			change:        contracts.ColumnTypeChange{Table: "users", Column: "name", Type: "string(255)"},
+			expectSql:     `alter table "goravel_users" alter column "name" type varchar(255)`,
+			expectRewrite: true,
+		},
+		{
+			name:      "longer varchar",
+			change:    contracts.ColumnTypeChange{Table: "users", Column: "name", From: "character varying(100)", Type: "string(255)"},
+			expectSql: `alter table "goravel_users" alter column "name" type varchar(255)`,
+		},
+		{
+			name:      "varchar to text with collation",
+			change:    contracts.ColumnTypeChange{Table: "users", Column: "name", From: "character varying(100)", Type: "text collate und-x-icu"},
+			expectSql: `alter table "goravel_users" alter column "name" type text collate "und-x-icu"`,
+		},
+		{
+			name:      "varchar to text with a libc collation",
+			change:    contracts.ColumnTypeChange{Table: "users", Column: "name", From: "character varying(100)", Type: "text collate en_US.utf8"},
+			expectSql: `alter table "goravel_users" alter column "name" type text collate "en_US.utf8"`,
+		},
+		{
+			name:          "varchar to integer",
+			change:        contracts.ColumnTypeChange{Table: "users", Column: "age", From: "character varying(255)", Type: "integer"},
+			expectSql:     `alter table "goravel_users" alter column "age" type integer using "age"::integer`,
+			expectRewrite: true,
+		},
+		{
+			name:          "text to jsonb",
+			change:        contracts.ColumnTypeChange{Table: "users", Column: "settings", From: "text", Type: "jsonb"},
+			expectSql:     `alter table "goravel_users" alter column "settings" type jsonb using "settings"::jsonb`,
+			expectRewrite: true,
+		},
+		{
+			name:          "timestamp to timestamptz",
+			change:        contracts.ColumnTypeChange{Table: "users", Column: "created_at", From: "timestamp(0) without time zone", Type: "timestampTz(0)"},
+			expectSql:     `alter table "goravel_users" alter column "created_at" type timestamp(0) with time zone using "created_at" at time zone 'UTC'`,
+			expectRewrite: true,
+		},
+		{
+			name:          "integer to boolean",
+			change:        contracts.ColumnTypeChange{Table: "users", Column: "active", From: "smallint", Type: "boolean"},
+			expectSql:     `alter table "goravel_users" alter column "active" type boolean using "active" <> 0`,
+			expectRewrite: true,
+		},
+		{
+			name:          "integer to bigint",
+			change:        contracts.ColumnTypeChange{Table: "users", Column: "id", From: "integer", Type: "bigInteger"},
+			expectSql:     `alter table "goravel_users" alter column "id" type bigint`,
+			expectRewrite: true,
+		},
+		{
+			name:          "explicit using",
+			change:        contracts.ColumnTypeChange{Table: "users", Column: "age", From: "character varying(255)", Type: "integer", Using: `nullif(trim("age"), '')::integer`},
+			expectSql:     `alter table "goravel_users" alter column "age" type integer using nullif(trim("age"), '')::integer`,
+			expectRewrite: true,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			sql, rewrite := s.grammar.CompileAlterColumnType(test.change)
+			s.Equal(test.expectSql, sql)
+			s.Equal(test.expectRewrite, rewrite)
+		})
+	}
+}
+
+func (s *GrammarSuite) TestCompileAlterPolicy() {
+	s.Equal(`alter policy "tenant_isolation" on "goravel_orders" to "app", public using (tenant_id = current_setting('app.tenant_id')::bigint)`,
+		s.grammar.CompileAlterPolicy(contracts.Policy{
+			Name:  "tenant_isolation",
+			Table: "orders",
+			Roles: []string{"app", "PUBLIC"},
+			Using: "tenant_id = current_setting('app.tenant_id')::bigint",
+		}))
+}
+
+func (s *GrammarSuite) TestCompileAlterRole() {
+	s.Equal(`alter role "reporting"`, s.grammar.CompileAlterRole(contracts.Role{Name: "reporting"}))
+	s.Equal(`alter role "reporting" with nologin connection limit 5 password null`, s.grammar.CompileAlterRole(contracts.Role{
+		Name:            "reporting",
+		Login:           convert.Pointer(false),
+		ConnectionLimit: convert.Pointer(int64(5)),
+		Password:        convert.Pointer(""),
+	}))
+}
+
+func (s *GrammarSuite) TestCompileAlterSequence() {
+	s.Equal(`alter sequence "invoice_numbers" increment by 2 restart with 5000 no cycle owned by none`, s.grammar.CompileAlterSequence(contracts.Sequence{
+		Name:      "invoice_numbers",
+		Increment: convert.Pointer(int64(2)),
+		Restart:   convert.Pointer(int64(5000)),
+		Cycle:     convert.Pointer(false),
+		OwnedBy:   "none",
+	}))
+}
+
 func (s *GrammarSuite) TestCompileChange() {
 	mockBlueprint := mocksdriver.NewBlueprint(s.T())
 	mockColumn := mocksdriver.NewColumnDefinition(s.T())
@@ -70,6 +225,41 @@ func (s *GrammarSuite) TestCompileChange() {
 	s.Equal([]string{
 		`alter table "goravel_users" alter column "name" type varchar(1), alter column "name" set default 'goravel', alter column "name" set not null`,
 	}, sql)
+
+	mockBlueprint = mocksdriver.NewBlueprint(s.T())
+	mockColumn = mocksdriver.NewColumnDefinition(s.T())
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	mockColumn.EXPECT().GetName().Return("payload").Times(5)
+	mockColumn.EXPECT().GetType().Return(`text collate en_US.utf8 compression lz4 storage external using coalesce("payload", '')`).Once()
+	mockColumn.EXPECT().GetDefault().Return(nil).Once()
+	mockColumn.EXPECT().GetNullable().Return(true).Once()
+	mockColumn.EXPECT().IsChange().Return(true).Times(7)
+	mockColumn.EXPECT().IsSetGeneratedAs().Return(false).Times(3)
+	mockColumn.EXPECT().GetAutoIncrement().Return(false).Once()
+
+	sql = s.grammar.CompileChange(mockBlueprint, &contractsdriver.Command{
+		Column: mockColumn,
+	})
+
+	s.Equal([]string{
+		`alter table "goravel_users" alter column "payload" type text collate "en_US.utf8" using coalesce("payload", ''), alter column "payload" set storage external, ` +
+			`alter column "payload" set compression lz4, alter column "payload" drop default, alter column "payload" drop not null`,
+	}, sql)
+
+	blueprint := schema.NewBlueprint(nil, "goravel_", "subscriptions")
+	blueprint.Column("grace", Interval("hour to second", 0)).Nullable().Change()
+	s.Equal([]string{
+		`alter table "goravel_subscriptions" alter column "grace" type interval hour to second(0), alter column "grace" drop default, alter column "grace" drop not null`,
+	}, s.grammar.CompileChange(blueprint, blueprint.GetCommands()[0]))
+}
+
+func (s *GrammarSuite) TestCompileChecks() {
+	sql, err := s.grammar.CompileChecks("public", "users")
+	s.NoError(err)
+	s.Equal("select con.conname as name, pg_get_constraintdef(con.oid) as definition from pg_constraint con "+
+		"join pg_class c on c.oid = con.conrelid join pg_namespace n on n.oid = c.relnamespace "+
+		"where con.contype = 'c' and c.relname = 'goravel_users' and n.nspname = 'public' order by con.conname", sql)
 }
 
 func (s *GrammarSuite) TestCompileColumns() {
@@ -85,12 +275,21 @@ func (s *GrammarSuite) TestCompileColumns() {
 			schema: "public",
 			table:  "users",
 			expectedSQL: `select a.attname as name, t.typname as type_name, format_type(a.atttypid, a.atttypmod) as type, ` +
-				`(select tc.collcollate from pg_catalog.pg_collation tc where tc.oid = a.attcollation) as collation, ` +
+				`(select tc.collname from pg_catalog.pg_collation tc where tc.oid = a.attcollation and a.attcollation <> t.typcollation) as collation, ` +
+				`case a.attcompression when 'p' then 'pglz' when 'l' then 'lz4' end as compression, ` +
+				`case when a.attstorage <> t.typstorage then case a.attstorage when 'p' then 'plain' when 'e' then 'external' ` +
+				`when 'm' then 'main' when 'x' then 'extended' end end as storage, ` +
 				`not a.attnotnull as nullable, ` +
-				`(select pg_get_expr(adbin, adrelid) from pg_attrdef where c.oid = pg_attrdef.adrelid and pg_attrdef.adnum = a.attnum) as default, ` +
+				`case when a.attgenerated = '' then pg_get_expr(d.adbin, d.adrelid) end as default, ` +
+				`case when a.attgenerated <> '' then pg_get_expr(d.adbin, d.adrelid) end as generation, ` +
+				`case a.attgenerated when 's' then 'stored' when 'v' then 'virtual' end as generated, ` +
+				`case a.attidentity when 'a' then 'always' when 'd' then 'by default' end as identity, ` +
+				`a.attidentity <> '' as autoincrement, a.attndims as dimensions, ` +
+				`case when t.typtype = 'd' then format_type(t.typbasetype, t.typtypmod) end as base_type, ` +
 				`col_description(c.oid, a.attnum) as comment ` +
-				`from pg_attribute a, pg_class c, pg_type t, pg_namespace n ` +
-				`where c.relname = 'goravel_users' and n.nspname = 'public' and a.attnum > 0 and a.attrelid = c.oid and a.atttypid = t.oid and n.oid = c.relnamespace ` +
+				`from pg_attribute a join pg_class c on c.oid = a.attrelid join pg_namespace n on n.oid = c.relnamespace ` +
+				`join pg_type t on t.oid = a.atttypid left join pg_attrdef d on d.adrelid = a.attrelid and d.adnum = a.attnum ` +
+				`where c.relname = 'goravel_users' and n.nspname = 'public' and a.attnum > 0 ` +
 				`order by a.attnum`,
 			expectedError: nil,
 		},
@@ -99,12 +298,21 @@ func (s *GrammarSuite) TestCompileColumns() {
 			schema: "public",
 			table:  "schema.users",
 			expectedSQL: `select a.attname as name, t.typname as type_name, format_type(a.atttypid, a.atttypmod) as type, ` +
-				`(select tc.collcollate from pg_catalog.pg_collation tc where tc.oid = a.attcollation) as collation, ` +
+				`(select tc.collname from pg_catalog.pg_collation tc where tc.oid = a.attcollation and a.attcollation <> t.typcollation) as collation, ` +
+				`case a.attcompression when 'p' then 'pglz' when 'l' then 'lz4' end as compression, ` +
+				`case when a.attstorage <> t.typstorage then case a.attstorage when 'p' then 'plain' when 'e' then 'external' ` +
+				`when 'm' then 'main' when 'x' then 'extended' end end as storage, ` +
 				`not a.attnotnull as nullable, ` +
-				`(select pg_get_expr(adbin, adrelid) from pg_attrdef where c.oid = pg_attrdef.adrelid and pg_attrdef.adnum = a.attnum) as default, ` +
+				`case when a.attgenerated = '' then pg_get_expr(d.adbin, d.adrelid) end as default, ` +
+				`case when a.attgenerated <> '' then pg_get_expr(d.adbin, d.adrelid) end as generation, ` +
+				`case a.attgenerated when 's' then 'stored' when 'v' then 'virtual' end as generated, ` +
+				`case a.attidentity when 'a' then 'always' when 'd' then 'by default' end as identity, ` +
+				`a.attidentity <> '' as autoincrement, a.attndims as dimensions, ` +
+				`case when t.typtype = 'd' then format_type(t.typbasetype, t.typtypmod) end as base_type, ` +
 				`col_description(c.oid, a.attnum) as comment ` +
-				`from pg_attribute a, pg_class c, pg_type t, pg_namespace n ` +
-				`where c.relname = 'goravel_users' and n.nspname = 'schema' and a.attnum > 0 and a.attrelid = c.oid and a.atttypid = t.oid and n.oid = c.relnamespace ` +
+				`from pg_attribute a join pg_class c on c.oid = a.attrelid join pg_namespace n on n.oid = c.relnamespace ` +
+				`join pg_type t on t.oid = a.atttypid left join pg_attrdef d on d.adrelid = a.attrelid and d.adnum = a.attnum ` +
+				`where c.relname = 'goravel_users' and n.nspname = 'schema' and a.attnum > 0 ` +
 				`order by a.attnum`,
 			expectedError: nil,
 		},
@@ -184,6 +392,318 @@ func (s *GrammarSuite) TestCompileCreate() {
 
 	s.Equal(`create table "goravel_users" ("id" serial primary key not null, "name" varchar(100) null)`,
 		s.grammar.CompileCreate(mockBlueprint))
+
+	blueprint := schema.NewBlueprint(nil, "goravel_", "subscriptions")
+	blueprint.Create()
+	blueprint.Column("term", Interval("year to month"))
+	blueprint.Column("grace", Interval("day to second", 3))
+	blueprint.Column("timeout", "interval(3)")
+	s.Equal(`create table "goravel_subscriptions" ("term" interval year to month not null, "grace" interval day to second(3) not null, "timeout" interval(3) not null)`,
+		s.grammar.CompileCreate(blueprint))
+}
+
+func (s *GrammarSuite) TestCompileCreateDomain() {
+	tests := []struct {
+		name      string
+		domain    contracts.Domain
+		expectSql string
+	}{
+		{
+			name:      "with grammar type",
+			domain:    contracts.Domain{Name: "email", Type: "string(255)"},
+			expectSql: `create domain "email" as varchar(255)`,
+		},
+		{
+			name:      "with raw type",
+			domain:    contracts.Domain{Name: "public.score", Type: "numeric(5,2)"},
+			expectSql: `create domain "public"."score" as numeric(5,2)`,
+		},
+		{
+			name: "with all options",
+			domain: contracts.Domain{
+				Name:      "email",
+				Type:      "text",
+				Collation: "und-x-icu",
+				Default:   "unknown@example.com",
+				NotNull:   true,
+				Checks: []contracts.Check{
+					{Name: "email_check", Expression: "VALUE ~ '^.+@.+$'"},
+					{Expression: "length(VALUE) < 255"},
+				},
+			},
+			expectSql: `create domain "email" as text collate "und-x-icu" default 'unknown@example.com' not null constraint "email_check" check (VALUE ~ '^.+@.+$') check (length(VALUE) < 255)`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.Equal(test.expectSql, s.grammar.CompileCreateDomain(test.domain))
+		})
+	}
+}
+
+func (s *GrammarSuite) TestCompileCreateExtension() {
+	s.Equal(`create extension if not exists "postgis"`, s.grammar.CompileCreateExtension(contracts.Extension{Name: "postgis"}))
+	s.Equal(`create extension if not exists "uuid-ossp" schema "extensions" version '1.1' cascade`, s.grammar.CompileCreateExtension(contracts.Extension{
+		Name:    "uuid-ossp",
+		Schema:  "extensions",
+		Version: "1.1",
+		Cascade: true,
+	}))
+}
+
+func (s *GrammarSuite) TestCompileCreateFunction() {
+	s.Equal(`create function "touch_updated_at"() returns trigger language plpgsql as $function$begin new.updated_at = now(); return new; end;$function$`,
+		s.grammar.CompileCreateFunction(contracts.Function{
+			Name:    "touch_updated_at",
+			Returns: "trigger",
+			Body:    "begin new.updated_at = now(); return new; end;",
+		}))
+	s.Equal(`create or replace function "billing"."add"(a integer, b integer) returns integer language sql immutable security definer as $function$select a + b$function$`,
+		s.grammar.CompileCreateFunction(contracts.Function{
+			Name:            "billing.add",
+			Arguments:       "a integer, b integer",
+			Returns:         "integer",
+			Language:        "sql",
+			Body:            "select a + b",
+			Volatility:      "immutable",
+			SecurityDefiner: true,
+			OrReplace:       true,
+		}))
+}
+
+func (s *GrammarSuite) TestCompileCreateIndex() {
+	s.Equal(`create index "users_name_trgm_index" on "goravel_users" using gin ("name" gin_trgm_ops)`, s.grammar.CompileCreateIndex(contracts.Index{
+		Table:         "users",
+		Name:          "users_name_trgm_index",
+		Columns:       []string{"name"},
+		Algorithm:     "gin",
+		OperatorClass: "gin_trgm_ops",
+	}))
+	s.Equal(`create unique index concurrently "users_email_index" on "goravel_users" using gist ("first_name" gist_trgm_ops, "last_name" gist_trgm_ops) `+
+		`with (buffering = auto, fillfactor = 90) where deleted_at is null`, s.grammar.CompileCreateIndex(contracts.Index{
+		Table:         "users",
+		Name:          "users_email_index",
+		Columns:       []string{"first_name", "last_name"},
+		Algorithm:     "gist",
+		OperatorClass: "gist_trgm_ops",
+		With:          map[string]any{"fillfactor": 90, "buffering": "auto"},
+		Where:         "deleted_at is null",
+		Unique:        true,
+		Concurrently:  true,
+	}))
+	s.Equal(`create index "items_embedding_index" on "goravel_items" using hnsw ("embedding" vector_cosine_ops) with (ef_construction = 64, m = 16)`,
+		s.grammar.CompileCreateIndex(contracts.Index{
+			Table:         "items",
+			Name:          "items_embedding_index",
+			Columns:       []string{"embedding"},
+			Algorithm:     "hnsw",
+			OperatorClass: "vector_cosine_ops",
+			With:          map[string]any{"m": 16, "ef_construction": 64},
+		}))
+	s.Equal(`create index "items_embedding_index" on "goravel_items" using ivfflat ("embedding" vector_l2_ops) with (lists = 100)`,
+		s.grammar.CompileCreateIndex(contracts.Index{
+			Table:         "items",
+			Name:          "items_embedding_index",
+			Columns:       []string{"embedding"},
+			Algorithm:     "ivfflat",
+			OperatorClass: "vector_l2_ops",
+			With:          map[string]any{"lists": 100},
+		}))
+}
+
+func (s *GrammarSuite) TestCompileCreateMaterializedView() {
+	s.Equal(`create materialized view "public"."daily_sales" as select date, sum(total) from orders group by date with data`, s.grammar.CompileCreateMaterializedView(contracts.MaterializedView{
+		Name:  "public.daily_sales",
+		Query: "select date, sum(total) from orders group by date",
+	}))
+	s.Equal(`create materialized view "daily_sales" as select 1 with no data`, s.grammar.CompileCreateMaterializedView(contracts.MaterializedView{
+		Name:   "daily_sales",
+		Query:  "select 1",
+		NoData: true,
+	}))
+}
+
+func (s *GrammarSuite) TestCompileCreatePolicy() {
+	s.Equal(`create policy "tenant_isolation" on "goravel_orders"`, s.grammar.CompileCreatePolicy(contracts.Policy{
+		Name:  "tenant_isolation",
+		Table: "orders",
+	}))
+	s.Equal(`create policy "tenant_isolation" on "goravel_orders" as restrictive for update to "app", current_user using (tenant_id = 1) with check (tenant_id = 1)`,
+		s.grammar.CompileCreatePolicy(contracts.Policy{
+			Name:      "tenant_isolation",
+			Table:     "orders",
+			As:        "restrictive",
+			Command:   "update",
+			Roles:     []string{"app", "current_user"},
+			Using:     "tenant_id = 1",
+			WithCheck: "tenant_id = 1",
+		}))
+}
+
+func (s *GrammarSuite) TestCompileCreateRangeType() {
+	s.Equal(`create type "timerange" as range (subtype = time)`, s.grammar.CompileCreateRangeType(contracts.RangeType{
+		Name:    "timerange",
+		Subtype: "time",
+	}))
+	s.Equal(`create type "public"."floatrange" as range (subtype = float8, subtype_opclass = float8_ops, collation = "C", canonical = floatrange_canonical, subtype_diff = float8mi, multirange_type_name = "floatmultirange")`, s.grammar.CompileCreateRangeType(contracts.RangeType{
+		Name:               "public.floatrange",
+		Subtype:            "float8",
+		SubtypeOpClass:     "float8_ops",
+		Collation:          "C",
+		Canonical:          "floatrange_canonical",
+		SubtypeDiff:        "float8mi",
+		MultirangeTypeName: "floatmultirange",
+	}))
+}
+
+func (s *GrammarSuite) TestCompileDropRole() {
+	s.Equal(`drop role if exists "reporting"`, s.grammar.CompileDropRole("reporting"))
+}
+
+func (s *GrammarSuite) TestCompileDropSchema() {
+	s.Equal(`drop schema if exists "billing"`, s.grammar.CompileDropSchema(contracts.Schema{Name: "billing"}))
+	s.Equal(`drop schema if exists "billing" cascade`, s.grammar.CompileDropSchema(contracts.Schema{Name: "billing", Cascade: true}))
+}
+
+func (s *GrammarSuite) TestCompileDropSequence() {
+	s.Equal(`drop sequence if exists "invoice_numbers"`, s.grammar.CompileDropSequence(contracts.Sequence{Name: "invoice_numbers"}))
+	s.Equal(`drop sequence if exists "invoice_numbers" cascade`, s.grammar.CompileDropSequence(contracts.Sequence{Name: "invoice_numbers", Cascade: true}))
+}
+
+func (s *GrammarSuite) TestCompileDropType() {
+	s.Equal(`drop type if exists "public"."timerange"`, s.grammar.CompileDropType("public.timerange"))
+}
+
+func (s *GrammarSuite) TestCompileCreateRole() {
+	s.Equal(`create role "readonly"`, s.grammar.CompileCreateRole(contracts.Role{Name: "readonly"}))
+	s.Equal(`create role "reporting" with nosuperuser inherit login password 'it''s' valid until '2030-01-01' in role "readonly", "analytics"`,
+		s.grammar.CompileCreateRole(contracts.Role{
+			Name:       "reporting",
+			Login:      convert.Pointer(true),
+			Superuser:  convert.Pointer(false),
+			Inherit:    convert.Pointer(true),
+			Password:   convert.Pointer("it's"),
+			ValidUntil: "2030-01-01",
+			InRoles:    []string{"readonly", "analytics"},
+		}))
+}
+
+func (s *GrammarSuite) TestCompileCreateSchema() {
+	s.Equal(`create schema "billing"`, s.grammar.CompileCreateSchema(contracts.Schema{Name: "billing"}))
+	s.Equal(`create schema if not exists "billing" authorization "billing_owner"`, s.grammar.CompileCreateSchema(contracts.Schema{
+		Name:          "billing",
+		Authorization: "billing_owner",
+		IfNotExists:   true,
+	}))
+}
+
+func (s *GrammarSuite) TestCompileCreateSequence() {
+	s.Equal(`create sequence "invoice_numbers"`, s.grammar.CompileCreateSequence(contracts.Sequence{Name: "invoice_numbers"}))
+	s.Equal(`create sequence if not exists "public"."invoice_numbers" as bigint increment by 1 minvalue 1000 maxvalue 999999 start with 1000 cache 10 cycle owned by "goravel_invoices"."number"`, s.grammar.CompileCreateSequence(contracts.Sequence{
+		Name:        "public.invoice_numbers",
+		As:          "bigint",
+		Increment:   convert.Pointer(int64(1)),
+		MinValue:    convert.Pointer(int64(1000)),
+		MaxValue:    convert.Pointer(int64(999999)),
+		Start:       convert.Pointer(int64(1000)),
+		Cache:       convert.Pointer(int64(10)),
+		Cycle:       convert.Pointer(true),
+		OwnedBy:     "invoices.number",
+		IfNotExists: true,
+	}))
+}
+
+func (s *GrammarSuite) TestCompileCreateTable() {
+	tests := []struct {
+		name     string
+		options  contracts.TableOptions
+		expected string
+	}{
+		{
+			name:     "without options",
+			expected: `create table "goravel_events" ("name" varchar(100) null)`,
+		},
+		{
+			name: "unlogged",
+			options: contracts.TableOptions{
+				IfNotExists: true,
+				Unlogged:    true,
+				With:        map[string]any{"fillfactor": 70, "autovacuum_vacuum_scale_factor": 0.01},
+				Tablespace:  "fast",
+			},
+			expected: `create unlogged table if not exists "goravel_events" ("name" varchar(100) null) with (autovacuum_vacuum_scale_factor = 0.01, fillfactor = 70) tablespace "fast"`,
+		},
+		{
+			name: "temporary",
+			options: contracts.TableOptions{
+				Temporary: true,
+				Unlogged:  true,
+				OnCommit:  "drop",
+			},
+			expected: `create temporary table "goravel_events" ("name" varchar(100) null) on commit drop`,
+		},
+		{
+			name: "inherits",
+			options: contracts.TableOptions{
+				Inherits: []string{"logs", "archive.logs"},
+			},
+			expected: `create table "goravel_events" ("name" varchar(100) null) inherits ("goravel_logs", "archive"."goravel_logs")`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.Equal(test.expected, s.grammar.CompileCreateTable(s.mockCreateBlueprint(), test.options))
+		})
+	}
+
+	s.grammar.tableOptions = map[string]contracts.TableOptions{
+		"events": {Unlogged: true},
+	}
+	s.Equal(`create unlogged table "goravel_events" ("name" varchar(100) null)`, s.grammar.CompileCreate(s.mockCreateBlueprint()))
+}
+
+func (s *GrammarSuite) TestCompileCreateTextSearchConfiguration() {
+	s.Equal([]string{
+		`create text search configuration "public"."english_unaccent" (copy = "english")`,
+		`alter text search configuration "public"."english_unaccent" alter mapping for asciiword, word with "unaccent", "english_stem"`,
+		`alter text search configuration "public"."english_unaccent" alter mapping for hword, hword_part with "unaccent", "simple"`,
+	}, s.grammar.CompileCreateTextSearchConfiguration(contracts.TextSearchConfiguration{
+		Name: "public.english_unaccent",
+		Copy: "english",
+		Mappings: map[string][]string{
+			"hword, hword_part": {"unaccent", "simple"},
+			"asciiword, word":   {"unaccent", "english_stem"},
+		},
+	}))
+}
+
+func (s *GrammarSuite) TestCompileCreateTrigger() {
+	s.Equal(`create trigger "users_touch" before update on "goravel_users" for each row execute function "touch_updated_at"()`,
+		s.grammar.CompileCreateTrigger(contracts.Trigger{
+			Name:     "users_touch",
+			Table:    "users",
+			Timing:   "before",
+			Events:   []string{"update"},
+			Function: "touch_updated_at",
+		}))
+	s.Equal(`create or replace trigger "users_audit" after insert or update of "name", "email" or delete on "goravel_users" for each statement when (pg_trigger_depth() = 0) execute function "audit"('users', 'it''s')`,
+		s.grammar.CompileCreateTrigger(contracts.Trigger{
+			Name:      "users_audit",
+			Table:     "users",
+			Timing:    "after",
+			Events:    []string{"insert", "update of name, email", "delete"},
+			ForEach:   "statement",
+			When:      "pg_trigger_depth() = 0",
+			Function:  "audit",
+			Arguments: []string{"users", "it's"},
+			OrReplace: true,
+		}))
+}
+
+func (s *GrammarSuite) TestCompileDisableRowLevelSecurity() {
+	s.Equal(`alter table "goravel_orders" disable row level security`, s.grammar.CompileDisableRowLevelSecurity("orders"))
 }
 
 func (s *GrammarSuite) TestCompileDropAllTables() {
@@ -209,7 +729,11 @@ func (s *GrammarSuite) TestCompileDropAllTypes() {
 
 func (s *GrammarSuite) TestCompileDropAllViews() {
 	s.Equal([]string{
-		`drop view "public"."domain", "public"."users" cascade`,
+		"do $$ declare v record; begin " +
+			"for v in select n.nspname, c.relname, c.relkind from pg_class c join pg_namespace n on n.oid = c.relnamespace " +
+			"where c.relkind in ('v', 'm') and (n.nspname, c.relname) in (('public', 'domain'), ('public', 'users')) loop " +
+			"execute format('drop %s if exists %I.%I cascade', case v.relkind when 'm' then 'materialized view' else 'view' end, v.nspname, v.relname); " +
+			"end loop; end $$",
 	}, s.grammar.CompileDropAllViews("public", []contractsdriver.View{
 		{Schema: "public", Name: "domain"},
 		{Schema: "public", Name: "users"},
@@ -226,6 +750,25 @@ func (s *GrammarSuite) TestCompileDropColumn() {
 	}))
 }
 
+func (s *GrammarSuite) TestCompileDropDomain() {
+	s.Equal(`drop domain if exists "public"."email"`, s.grammar.CompileDropDomain("public.email"))
+	s.Equal(`alter domain "email" drop constraint if exists "email_check"`, s.grammar.CompileDropDomainConstraint("email", "email_check"))
+}
+
+func (s *GrammarSuite) TestCompileDropExtension() {
+	s.Equal(`drop extension if exists "pg_trgm"`, s.grammar.CompileDropExtension(contracts.Extension{Name: "pg_trgm"}))
+	s.Equal(`drop extension if exists "postgis" cascade`, s.grammar.CompileDropExtension(contracts.Extension{Name: "postgis", Cascade: true}))
+}
+
+func (s *GrammarSuite) TestCompileDropFunction() {
+	s.Equal(`drop function if exists "touch_updated_at"`, s.grammar.CompileDropFunction(contracts.Function{Name: "touch_updated_at"}))
+	s.Equal(`drop function if exists "add"(integer, integer) cascade`, s.grammar.CompileDropFunction(contracts.Function{
+		Name:      "add",
+		Arguments: "integer, integer",
+		Cascade:   true,
+	}))
+}
+
 func (s *GrammarSuite) TestCompileDropIfExists() {
 	mockBlueprint := mocksdriver.NewBlueprint(s.T())
 	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
@@ -233,6 +776,22 @@ func (s *GrammarSuite) TestCompileDropIfExists() {
 	s.Equal(`drop table if exists "goravel_users"`, s.grammar.CompileDropIfExists(mockBlueprint))
 }
 
+func (s *GrammarSuite) TestCompileDropTextSearchConfiguration() {
+	s.Equal(`drop text search configuration if exists "english_unaccent"`, s.grammar.CompileDropTextSearchConfiguration("english_unaccent"))
+}
+
+func (s *GrammarSuite) TestCompileDropTrigger() {
+	s.Equal(`drop trigger if exists "users_touch" on "goravel_users"`, s.grammar.CompileDropTrigger("users", "users_touch"))
+}
+
+func (s *GrammarSuite) TestCompileDropMaterializedView() {
+	s.Equal(`drop materialized view if exists "daily_sales"`, s.grammar.CompileDropMaterializedView("daily_sales"))
+}
+
+func (s *GrammarSuite) TestCompileDropPolicy() {
+	s.Equal(`drop policy if exists "tenant_isolation" on "goravel_orders"`, s.grammar.CompileDropPolicy("orders", "tenant_isolation"))
+}
+
 func (s *GrammarSuite) TestCompileDropPrimary() {
 	mockBlueprint := mocksdriver.NewBlueprint(s.T())
 	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
@@ -240,6 +799,15 @@ func (s *GrammarSuite) TestCompileDropPrimary() {
 	s.Equal(`alter table "goravel_users" drop constraint "goravel_users_pkey"`, s.grammar.CompileDropPrimary(mockBlueprint, &contractsdriver.Command{}))
 }
 
+func (s *GrammarSuite) TestCompileEnableRowLevelSecurity() {
+	s.Equal(`alter table "goravel_orders" enable row level security`, s.grammar.CompileEnableRowLevelSecurity("orders"))
+}
+
+func (s *GrammarSuite) TestCompileForceRowLevelSecurity() {
+	s.Equal(`alter table "goravel_orders" force row level security`, s.grammar.CompileForceRowLevelSecurity("orders", true))
+	s.Equal(`alter table "goravel_orders" no force row level security`, s.grammar.CompileForceRowLevelSecurity("orders", false))
+}
+
 func (s *GrammarSuite) TestCompileForeign() {
 	var mockBlueprint *mocksdriver.Blueprint
 
@@ -275,6 +843,17 @@ func (s *GrammarSuite) TestCompileForeign() {
 			},
 			expectSql: `alter table "goravel_users" add constraint "fk_users_role_id" foreign key ("role_id", "user_id") references "goravel_roles" ("id", "user_id")`,
 		},
+		{
+			name: "deferrable",
+			command: &contractsdriver.Command{
+				Index:      "fk_users_role_id",
+				Columns:    []string{"role_id"},
+				On:         "roles",
+				References: []string{"id"},
+				Deferrable: convert.Pointer(true),
+			},
+			expectSql: `alter table "goravel_users" add constraint "fk_users_role_id" foreign key ("role_id") references "goravel_roles" ("id") deferrable`,
+		},
 	}
 
 	for _, test := range tests {
@@ -285,6 +864,18 @@ func (s *GrammarSuite) TestCompileForeign() {
 			s.Equal(test.expectSql, sql)
 		})
 	}
+
+	beforeEach()
+	s.grammar.foreignKeyOptions = contracts.ForeignKeyOptions{Match: "full", NotValid: true}
+	s.Equal(`alter table "goravel_users" add constraint "fk_users_role_id" foreign key ("role_id") references "goravel_roles" ("id") match full on delete cascade deferrable not valid`,
+		s.grammar.CompileForeign(mockBlueprint, &contractsdriver.Command{
+			Index:      "fk_users_role_id",
+			Columns:    []string{"role_id"},
+			On:         "roles",
+			References: []string{"id"},
+			OnDelete:   "cascade",
+			Deferrable: convert.Pointer(true),
+		}))
 }
 
 func (s *GrammarSuite) TestCompileFullText() {
@@ -297,6 +888,99 @@ func (s *GrammarSuite) TestCompileFullText() {
 	}))
 }
 
+func (s *GrammarSuite) TestCompileFullTextHeadline() {
+	sql, bindings := s.grammar.CompileFullTextHeadline(contracts.FullTextSearch{Columns: []string{"title", "body"}}, "body", "postgres", map[string]any{
+		"StartSel": "<b>",
+		"StopSel":  "</b>",
+		"MaxWords": 35,
+	})
+	s.Equal(`ts_headline('english', "body", websearch_to_tsquery('english', ?), 'MaxWords = 35, StartSel = <b>, StopSel = </b>')`, sql)
+	s.Equal([]any{"postgres"}, bindings)
+
+	sql, bindings = s.grammar.CompileFullTextHeadline(contracts.FullTextSearch{Language: "simple", Mode: "phrase"}, "body", "full text", nil)
+	s.Equal(`ts_headline('simple', "body", phraseto_tsquery('simple', ?))`, sql)
+	s.Equal([]any{"full text"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileFullTextMatch() {
+	sql, bindings := s.grammar.CompileFullTextMatch(contracts.FullTextSearch{Columns: []string{"title", "body"}}, `"fast search" -slow`, false)
+	s.Equal(`(to_tsvector('english', "title") || to_tsvector('english', "body")) @@ websearch_to_tsquery('english', ?)`, sql)
+	s.Equal([]any{`"fast search" -slow`}, bindings)
+
+	sql, bindings = s.grammar.CompileFullTextMatch(contracts.FullTextSearch{
+		Columns:  []string{"title"},
+		Language: "public.english_unaccent",
+		Mode:     "plain",
+	}, "cafe", true)
+	s.Equal(`not (to_tsvector('public.english_unaccent', "title")) @@ plainto_tsquery('public.english_unaccent', ?)`, sql)
+	s.Equal([]any{"cafe"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileFullTextOrderByRank() {
+	sql, bindings := s.grammar.CompileFullTextOrderByRank(contracts.FullTextSearch{Columns: []string{"title"}}, "postgres")
+	s.Equal(`ts_rank(to_tsvector('english', "title"), websearch_to_tsquery('english', ?)) desc`, sql)
+	s.Equal([]any{"postgres"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileFullTextRank() {
+	sql, bindings := s.grammar.CompileFullTextRank(contracts.FullTextSearch{
+		Columns:       []string{"title", "body", "tags"},
+		Weights:       map[string]string{"title": "a", "body": "B"},
+		Normalization: 32,
+		CoverDensity:  true,
+	}, "postgres")
+	s.Equal(`ts_rank_cd(setweight(to_tsvector('english', "title"), 'A') || setweight(to_tsvector('english', "body"), 'B') || `+
+		`setweight(to_tsvector('english', "tags"), 'D'), websearch_to_tsquery('english', ?), 32)`, sql)
+	s.Equal([]any{"postgres"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileGrant() {
+	tests := []struct {
+		name     string
+		grant    contracts.Grant
+		expected string
+	}{
+		{
+			name:     "tables",
+			grant:    contracts.Grant{Privileges: []string{"select", "insert"}, ObjectType: "table", Objects: []string{"users", "public.orders"}, Roles: []string{"reporting"}},
+			expected: `grant select, insert on table "goravel_users", "public"."goravel_orders" to "reporting"`,
+		},
+		{
+			name:     "sequences",
+			grant:    contracts.Grant{Privileges: []string{"usage"}, ObjectType: "sequence", Objects: []string{"billing.invoice_number"}, Roles: []string{"billing"}},
+			expected: `grant usage on sequence "billing"."invoice_number" to "billing"`,
+		},
+		{
+			name:     "schemas",
+			grant:    contracts.Grant{Privileges: []string{"usage"}, ObjectType: "schema", Objects: []string{"billing"}, Roles: []string{"reporting", "public"}, WithGrantOption: true},
+			expected: `grant usage on schema "billing" to "reporting", public with grant option`,
+		},
+		{
+			name:     "functions",
+			grant:    contracts.Grant{Privileges: []string{"execute"}, ObjectType: "function", Objects: []string{"add(integer, integer)"}, Roles: []string{"app"}},
+			expected: `grant execute on function "add"(integer, integer) to "app"`,
+		},
+		{
+			name:     "all tables in schema",
+			grant:    contracts.Grant{ObjectType: "all tables in schema", Objects: []string{"public"}, Roles: []string{"admin"}},
+			expected: `grant all privileges on all tables in schema "public" to "admin"`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.Equal(test.expected, s.grammar.CompileGrant(test.grant))
+		})
+	}
+}
+
+func (s *GrammarSuite) TestCompileGrantDefaultPrivileges() {
+	s.Equal(`alter default privileges for role "migrator" in schema "public" grant select on tables to "reporting"`,
+		s.grammar.CompileGrantDefaultPrivileges("migrator", "public", contracts.Grant{Privileges: []string{"select"}, ObjectType: "table", Roles: []string{"reporting"}}))
+	s.Equal(`alter default privileges grant usage on sequences to "app" with grant option`,
+		s.grammar.CompileGrantDefaultPrivileges("", "", contracts.Grant{Privileges: []string{"usage"}, ObjectType: "sequences", Roles: []string{"app"}, WithGrantOption: true}))
+}
+
 func (s *GrammarSuite) TestCompileIndex() {
 	var mockBlueprint *mocksdriver.Blueprint
 
@@ -609,6 +1293,45 @@ func (s *GrammarSuite) TestCompileJsonValues() {
 	}
 }
 
+func (s *GrammarSuite) TestCompileRange() {
+	sql, args := s.grammar.CompileRangeContains("period", "[2024-01-01,2024-02-01)", false)
+	s.Equal(`"period" @> ?`, sql)
+	s.Equal([]any{"[2024-01-01,2024-02-01)"}, args)
+
+	sql, args = s.grammar.CompileRangeContains("period", "[2024-01-01,2024-02-01)", true)
+	s.Equal(`not "period" @> ?`, sql)
+	s.Equal([]any{"[2024-01-01,2024-02-01)"}, args)
+
+	sql, args = s.grammar.CompileRangeContainsElement("period", "2024-01-01", "date", false)
+	s.Equal(`"period" @> ?::date`, sql)
+	s.Equal([]any{"2024-01-01"}, args)
+
+	sql, args = s.grammar.CompileRangeContainsElement("period", "2024-01-01 10:00:00+00", "timestamp with time zone", true)
+	s.Equal(`not "period" @> ?::timestamp with time zone`, sql)
+	s.Equal([]any{"2024-01-01 10:00:00+00"}, args)
+
+	sql, args = s.grammar.CompileRangeContainedBy("period", "[2024-01-01,2025-01-01)", false)
+	s.Equal(`"period" <@ ?`, sql)
+	s.Equal([]any{"[2024-01-01,2025-01-01)"}, args)
+
+	sql, args = s.grammar.CompileRangeOverlaps("bookings.period", "[1,10)", false)
+	s.Equal(`"goravel_bookings"."period" && ?`, sql)
+	s.Equal([]any{"[1,10)"}, args)
+
+	sql, args = s.grammar.CompileRangeAdjacent("period", "[10,20)", false)
+	s.Equal(`"period" -|- ?`, sql)
+	s.Equal([]any{"[10,20)"}, args)
+
+	s.Equal(`lower("period")`, s.grammar.CompileRangeLower("period"))
+	s.Equal(`upper("period")`, s.grammar.CompileRangeUpper("period"))
+}
+
+func (s *GrammarSuite) TestCompilePolicies() {
+	sql, err := s.grammar.CompilePolicies("public", "orders")
+	s.NoError(err)
+	s.Contains(sql, "from pg_policies where tablename = 'goravel_orders' and schemaname = 'public'")
+}
+
 func (s *GrammarSuite) TestCompilePrimary() {
 	mockBlueprint := mocksdriver.NewBlueprint(s.T())
 	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
@@ -618,6 +1341,11 @@ func (s *GrammarSuite) TestCompilePrimary() {
 	}))
 }
 
+func (s *GrammarSuite) TestCompileRefreshMaterializedView() {
+	s.Equal(`refresh materialized view "public"."daily_sales"`, s.grammar.CompileRefreshMaterializedView("public.daily_sales", false))
+	s.Equal(`refresh materialized view concurrently "daily_sales"`, s.grammar.CompileRefreshMaterializedView("daily_sales", true))
+}
+
 func (s *GrammarSuite) TestCompileRenameColumn() {
 	mockBlueprint := mocksdriver.NewBlueprint(s.T())
 	mockColumn := mocksdriver.NewColumnDefinition(s.T())
@@ -634,6 +1362,195 @@ func (s *GrammarSuite) TestCompileRenameColumn() {
 	s.Equal(`alter table "goravel_users" rename column "before" to "after"`, sql)
 }
 
+func (s *GrammarSuite) TestCompileSetHnswEfSearch() {
+	s.Equal("set local hnsw.ef_search = 100", s.grammar.CompileSetHnswEfSearch(100))
+}
+
+func (s *GrammarSuite) TestCompileSetIvfflatProbes() {
+	s.Equal("set local ivfflat.probes = 10", s.grammar.CompileSetIvfflatProbes(10))
+}
+
+func (s *GrammarSuite) TestCompileSetStorageParameters() {
+	s.Equal(`alter table "goravel_events" set (autovacuum_enabled = false, fillfactor = 70)`, s.grammar.CompileSetStorageParameters("events", map[string]any{
+		"fillfactor":         70,
+		"autovacuum_enabled": false,
+	}))
+}
+
+func (s *GrammarSuite) TestCompileSetTrigramSimilarityThreshold() {
+	sql, err := s.grammar.CompileSetTrigramSimilarityThreshold(0.4)
+	s.NoError(err)
+	s.Equal("set local pg_trgm.similarity_threshold = 0.4", sql)
+
+	sql, err = s.grammar.CompileSetTrigramSimilarityThreshold(0.00001)
+	s.NoError(err)
+	s.Equal("set local pg_trgm.similarity_threshold = 0.00001", sql)
+
+	for _, threshold := range []float64{-0.1, 1.5, math.NaN()} {
+		_, err = s.grammar.CompileSetTrigramSimilarityThreshold(threshold)
+		s.True(errors.Is(err, InvalidThreshold))
+	}
+}
+
+func (s *GrammarSuite) TestCompileSetTrigramWordSimilarityThreshold() {
+	sql, err := s.grammar.CompileSetTrigramWordSimilarityThreshold(1)
+	s.NoError(err)
+	s.Equal("set local pg_trgm.word_similarity_threshold = 1", sql)
+
+	_, err = s.grammar.CompileSetTrigramWordSimilarityThreshold(2)
+	s.EqualError(err, "the word_similarity threshold must be between 0 and 1, got 2")
+}
+
+func (s *GrammarSuite) TestCompileSpatial() {
+	sql, args := s.grammar.CompileSpatialDWithin("location", "SRID=4326;POINT(1 2)", 1000, false)
+	s.Equal(`ST_DWithin("location", ST_GeomFromEWKT(?), ?)`, sql)
+	s.Equal([]any{"SRID=4326;POINT(1 2)", float64(1000)}, args)
+
+	sql, args = s.grammar.CompileSpatialContains("area", "POINT(1 2)", false)
+	s.Equal(`ST_Contains("area", ST_GeomFromEWKT(?))`, sql)
+	s.Equal([]any{"POINT(1 2)"}, args)
+
+	sql, args = s.grammar.CompileSpatialIntersects("area", "LINESTRING(0 0, 1 1)", true)
+	s.Equal(`not ST_Intersects("area", ST_GeomFromEWKT(?))`, sql)
+	s.Equal([]any{"LINESTRING(0 0, 1 1)"}, args)
+
+	sql, args = s.grammar.CompileSpatialOrderByDistance("location", "POINT(1 2)")
+	s.Equal(`"location" <-> ST_GeomFromEWKT(?)`, sql)
+	s.Equal([]any{"POINT(1 2)"}, args)
+}
+
+func (s *GrammarSuite) TestCompileSpatialIndex() {
+	mockBlueprint := mocksdriver.NewBlueprint(s.T())
+	mockBlueprint.EXPECT().GetTableName().Return("places").Once()
+
+	command := &contractsdriver.Command{
+		Index:   "places_location_spatialindex",
+		Columns: []string{"location"},
+	}
+	s.Equal(`create index "places_location_spatialindex" on "goravel_places" using gist ("location")`, s.grammar.CompileSpatialIndex(mockBlueprint, command))
+	s.Empty(command.Algorithm)
+}
+
+func (s *GrammarSuite) TestCompileRevoke() {
+	s.Equal(`revoke insert, update on table "goravel_users" from "reporting"`,
+		s.grammar.CompileRevoke(contracts.Grant{Privileges: []string{"insert", "update"}, ObjectType: "table", Objects: []string{"users"}, Roles: []string{"reporting"}}))
+	s.Equal(`revoke grant option for all privileges on schema "billing" from "billing" cascade`,
+		s.grammar.CompileRevoke(contracts.Grant{ObjectType: "schema", Objects: []string{"billing"}, Roles: []string{"billing"}, WithGrantOption: true, Cascade: true}))
+}
+
+func (s *GrammarSuite) TestCompileRevokeDefaultPrivileges() {
+	s.Equal(`alter default privileges in schema "public" revoke execute on functions from public`,
+		s.grammar.CompileRevokeDefaultPrivileges("", "public", contracts.Grant{Privileges: []string{"execute"}, ObjectType: "function", Roles: []string{"public"}}))
+}
+
+func (s *GrammarSuite) TestCompileResetStorageParameters() {
+	s.Equal(`alter table "goravel_events" reset (fillfactor, autovacuum_enabled)`, s.grammar.CompileResetStorageParameters("events", []string{"fillfactor", "autovacuum_enabled"}))
+}
+
+func (s *GrammarSuite) TestCompileRenameSchema() {
+	s.Equal(`alter schema "billing" rename to "invoicing"`, s.grammar.CompileRenameSchema("billing", "invoicing"))
+}
+
+func (s *GrammarSuite) TestCompileResyncSequences() {
+	sql := s.grammar.CompileResyncSequences("public", "")
+	s.Contains(sql, "where c.relkind in ('r', 'p') and a.attnum > 0 and not a.attisdropped and n.nspname = 'public' and pg_get_serial_sequence")
+	s.Contains(sql, "perform setval(s.seq_name::regclass, last_id, true)")
+
+	sql = s.grammar.CompileResyncSequences("public", "users")
+	s.Contains(sql, "n.nspname = 'public' and c.relname = 'goravel_users' and pg_get_serial_sequence")
+}
+
+func (s *GrammarSuite) TestCompileSetLogged() {
+	s.Equal(`alter table "goravel_events" set logged`, s.grammar.CompileSetLogged("events", true))
+	s.Equal(`alter table "goravel_events" set unlogged`, s.grammar.CompileSetLogged("events", false))
+}
+
+func (s *GrammarSuite) TestCompileSetNotNull() {
+	s.Equal([]string{
+		`alter table "goravel_users" add constraint "goravel_users_email_not_null" check ("email" is not null) not valid`,
+		`alter table "goravel_users" validate constraint "goravel_users_email_not_null"`,
+		`alter table "goravel_users" alter column "email" set not null`,
+		`alter table "goravel_users" drop constraint "goravel_users_email_not_null"`,
+	}, s.grammar.CompileSetNotNull("users", "email"))
+	s.Equal(`alter table "billing"."goravel_invoices" add constraint "goravel_invoices_number_not_null" check ("number" is not null) not valid`,
+		s.grammar.CompileSetNotNull("billing.invoices", "number")[0])
+}
+
+func (s *GrammarSuite) TestCompileSetOwner() {
+	s.Equal(`alter table "goravel_users" owner to "migrator"`, s.grammar.CompileSetOwner("users", "migrator"))
+	s.Equal(`alter table "goravel_users" owner to current_user`, s.grammar.CompileSetOwner("users", "current_user"))
+}
+
+func (s *GrammarSuite) TestCompileSetSchema() {
+	s.Equal(`alter table "goravel_invoices" set schema "billing"`, s.grammar.CompileSetSchema("invoices", "billing"))
+	s.Equal(`alter table "public"."goravel_invoices" set schema "billing"`, s.grammar.CompileSetSchema("public.invoices", "billing"))
+}
+
+func (s *GrammarSuite) TestCompileTablePrivileges() {
+	sql, err := s.grammar.CompileTablePrivileges("public", "users")
+	s.NoError(err)
+	s.Contains(sql, "from information_schema.role_table_grants where table_name = 'goravel_users' and table_schema = 'public'")
+}
+
+func (s *GrammarSuite) TestCompileTrigramOrderByDistance() {
+	sql, bindings := s.grammar.CompileTrigramOrderByDistance("name", "jon")
+	s.Equal(`"name" <-> ?`, sql)
+	s.Equal([]any{"jon"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileTrigramSimilar() {
+	sql, bindings := s.grammar.CompileTrigramSimilar("name", "jon", false)
+	s.Equal(`"name" % ?`, sql)
+	s.Equal([]any{"jon"}, bindings)
+
+	sql, bindings = s.grammar.CompileTrigramSimilar("name", "jon", true)
+	s.Equal(`not "name" % ?`, sql)
+	s.Equal([]any{"jon"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileTrigramSimilarity() {
+	sql, bindings := s.grammar.CompileTrigramSimilarity("name", "jon")
+	s.Equal(`similarity("name", ?)`, sql)
+	s.Equal([]any{"jon"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileTrigramWordSimilar() {
+	sql, bindings := s.grammar.CompileTrigramWordSimilar("name", "jon", false)
+	s.Equal(`? <% "name"`, sql)
+	s.Equal([]any{"jon"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileTrigramWordSimilarity() {
+	sql, bindings := s.grammar.CompileTrigramWordSimilarity("name", "jon")
+	s.Equal(`word_similarity(?, "name")`, sql)
+	s.Equal([]any{"jon"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileVectorOrderBy() {
+	sql, bindings := s.grammar.CompileVectorOrderByL2Distance("embedding", []float32{1, 2, 3})
+	s.Equal(`"embedding" <-> ?`, sql)
+	s.Equal([]any{"[1,2,3]"}, bindings)
+
+	sql, bindings = s.grammar.CompileVectorOrderByCosineDistance("embedding", []float64{0.5, 0.25})
+	s.Equal(`"embedding" <=> ?`, sql)
+	s.Equal([]any{"[0.5,0.25]"}, bindings)
+
+	sql, bindings = s.grammar.CompileVectorOrderByInnerProduct("embedding", "{1:1,3:2}/5")
+	s.Equal(`"embedding" <#> ?`, sql)
+	s.Equal([]any{"{1:1,3:2}/5"}, bindings)
+}
+
+func (s *GrammarSuite) TestCompileTriggers() {
+	sql, err := s.grammar.CompileTriggers("public", "users")
+	s.NoError(err)
+	s.Contains(sql, "pg_get_triggerdef(t.oid) as definition from pg_trigger t")
+	s.Contains(sql, "where not t.tgisinternal and c.relname = 'goravel_users' and n.nspname = 'public'")
+
+	sql, err = s.grammar.CompileTriggers("", "billing.users")
+	s.NoError(err)
+	s.Contains(sql, "where not t.tgisinternal and c.relname = 'goravel_users' and n.nspname = 'billing'")
+}
+
 func (s *GrammarSuite) TestCompileUnique() {
 	tests := []struct {
 		name               string
@@ -676,6 +1593,25 @@ func (s *GrammarSuite) TestCompileUnique() {
 	}
 }
 
+func (s *GrammarSuite) TestCompileUpdateExtension() {
+	s.Equal(`alter extension "postgis" update`, s.grammar.CompileUpdateExtension(contracts.Extension{Name: "postgis"}))
+	s.Equal(`alter extension "pgcrypto" update to '1.3'`, s.grammar.CompileUpdateExtension(contracts.Extension{Name: "pgcrypto", Version: "1.3"}))
+}
+
+func (s *GrammarSuite) TestCompileValidateConstraint() {
+	s.Equal(`alter table "goravel_orders" validate constraint "orders_user_id_foreign"`, s.grammar.CompileValidateConstraint("orders", "orders_user_id_foreign"))
+}
+
+func (s *GrammarSuite) TestCompileValidateConstraints() {
+	s.Equal(`do $do$ declare c record; begin for c in select conname from pg_constraint where conrelid = '"public"."goravel_orders"'::regclass and not convalidated order by conname loop `+
+		`execute format('alter table %s validate constraint %I', '"public"."goravel_orders"', c.conname); end loop; end $do$`,
+		s.grammar.CompileValidateConstraints("public.orders"))
+}
+
+func (s *GrammarSuite) TestCompileValidateDomainConstraint() {
+	s.Equal(`alter domain "positive" validate constraint "positive_check"`, s.grammar.CompileValidateDomainConstraint("positive", "positive_check"))
+}
+
 func (s *GrammarSuite) TestGetColumns() {
 	mockColumn1 := mocksdriver.NewColumnDefinition(s.T())
 	mockColumn2 := mocksdriver.NewColumnDefinition(s.T())
@@ -705,6 +1641,94 @@ func (s *GrammarSuite) TestGetColumns() {
 	s.Equal([]string{"\"id\" serial primary key not null", "\"name\" varchar(10) default 'goravel' null"}, s.grammar.getColumns(mockBlueprint))
 }
 
+func (s *GrammarSuite) TestGetType() {
+	tests := []struct {
+		name             string
+		setup            func(mockColumn *mocksdriver.ColumnDefinition)
+		expectSql        string
+		expectAttributes columnAttributes
+	}{
+		{
+			name: "scalar",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("string").Once()
+				mockColumn.EXPECT().GetLength().Return(100).Once()
+			},
+			expectSql: "varchar(100)",
+		},
+		{
+			name: "array",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("string[]").Once()
+				mockColumn.EXPECT().GetLength().Return(100).Once()
+			},
+			expectSql: "varchar(100)[]",
+		},
+		{
+			name: "array of auto increment integer",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("integer[]").Once()
+			},
+			expectSql: "integer[]",
+		},
+		{
+			name: "with modifiers",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("decimal(10, 2)").Once()
+			},
+			expectSql: "decimal(10, 2)",
+		},
+		{
+			name: "vector",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("halfVector(1536)").Once()
+			},
+			expectSql: "halfvec(1536)",
+		},
+		{
+			name: "with modifiers of raw type",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("numeric(10,2)").Once()
+			},
+			expectSql: "numeric(10,2)",
+		},
+		{
+			name: "array with modifiers",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("bit(8)[]").Once()
+			},
+			expectSql: "bit(8)[]",
+		},
+		{
+			name: "multi-dimensional array of raw type",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("text[][]").Once()
+			},
+			expectSql: "text[][]",
+		},
+		{
+			name: "with attributes",
+			setup: func(mockColumn *mocksdriver.ColumnDefinition) {
+				mockColumn.EXPECT().GetType().Return("string[] collate und-x-icu storage external compression lz4").Once()
+				mockColumn.EXPECT().GetLength().Return(100).Once()
+			},
+			expectSql:        "varchar(100)[]",
+			expectAttributes: columnAttributes{collation: "und-x-icu", compression: "lz4", storage: "external"},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			mockColumn := mocksdriver.NewColumnDefinition(s.T())
+			test.setup(mockColumn)
+
+			ttype, attributes := s.grammar.getType(mockColumn)
+			s.Equal(test.expectSql, ttype)
+			s.Equal(test.expectAttributes, attributes)
+		})
+	}
+}
+
 func (s *GrammarSuite) TestEscapeNames() {
 	// SingleName
 	names := []string{"username"}
@@ -722,6 +1746,96 @@ func (s *GrammarSuite) TestEscapeNames() {
 	s.Equal(expected, s.grammar.EscapeNames(names))
 }
 
+func (s *GrammarSuite) TestIdempotent() {
+	s.grammar.idempotent = true
+
+	mockBlueprint := mocksdriver.NewBlueprint(s.T())
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	mockColumn.EXPECT().GetName().Return("name").Once()
+	mockColumn.EXPECT().GetType().Return("string").Twice()
+	mockColumn.EXPECT().GetLength().Return(1).Once()
+	mockColumn.EXPECT().GetDefault().Return(nil).Once()
+	mockColumn.EXPECT().GetNullable().Return(true).Once()
+	mockColumn.EXPECT().IsChange().Return(false).Times(4)
+	mockColumn.EXPECT().IsSetGeneratedAs().Return(false).Twice()
+	mockBlueprint.EXPECT().HasCommand("primary").Return(false).Once()
+	s.Equal(`alter table "goravel_users" add column if not exists "name" varchar(1) null`,
+		s.grammar.CompileAdd(mockBlueprint, &contractsdriver.Command{Column: mockColumn}))
+
+	s.Equal(`create table if not exists "goravel_events" ("name" varchar(100) null)`,
+		s.grammar.CompileCreateTable(s.mockCreateBlueprint(), contracts.TableOptions{}))
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	s.Equal(`drop table if exists "goravel_users"`, s.grammar.CompileDrop(mockBlueprint))
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	s.Equal([]string{`alter table "goravel_users" drop column if exists "name", drop column if exists "email"`},
+		s.grammar.CompileDropColumn(mockBlueprint, &contractsdriver.Command{Columns: []string{"name", "email"}}))
+
+	s.Equal(`drop index if exists "users_name_index"`, s.grammar.CompileDropIndex(mockBlueprint, &contractsdriver.Command{Index: "users_name_index"}))
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	s.Equal(`alter table "goravel_users" drop constraint if exists "users_email_unique"`,
+		s.grammar.CompileDropUnique(mockBlueprint, &contractsdriver.Command{Index: "users_email_unique"}))
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	s.Equal(`alter table "goravel_users" drop constraint if exists "users_role_id_foreign"`,
+		s.grammar.CompileDropForeign(mockBlueprint, &contractsdriver.Command{Index: "users_role_id_foreign"}))
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	s.Equal(`create index if not exists "users_name_index" on "goravel_users" ("name")`,
+		s.grammar.CompileIndex(mockBlueprint, &contractsdriver.Command{Index: "users_name_index", Columns: []string{"name"}}))
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	s.Equal(`do $do$ begin if not exists (select 1 from pg_constraint where conrelid = '"goravel_users"'::regclass and conname = 'users_email_unique') `+
+		`then alter table "goravel_users" add constraint "users_email_unique" unique ("email"); end if; end $do$`,
+		s.grammar.CompileUnique(mockBlueprint, &contractsdriver.Command{Index: "users_email_unique", Columns: []string{"email"}}))
+
+	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
+	s.Equal(`do $do$ begin if not exists (select 1 from pg_constraint where conrelid = '"goravel_users"'::regclass and contype = 'p') `+
+		`then alter table "goravel_users" add primary key ("id"); end if; end $do$`,
+		s.grammar.CompilePrimary(mockBlueprint, &contractsdriver.Command{Columns: []string{"id"}}))
+
+	s.Equal(`do $do$ begin if not exists (select 1 from pg_constraint where conrelid = '"goravel_orders"'::regclass and conname = 'orders_user_id_foreign') `+
+		`then alter table "goravel_orders" add constraint "orders_user_id_foreign" foreign key ("user_id") references "goravel_users" ("id"); end if; end $do$`,
+		s.grammar.CompileAddForeignKey(contracts.ForeignKey{
+			Table:      "orders",
+			Name:       "orders_user_id_foreign",
+			Columns:    []string{"user_id"},
+			On:         "users",
+			References: []string{"id"},
+		}))
+}
+
+func (s *GrammarSuite) TestModifyCollation() {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	s.Empty(s.grammar.ModifyCollation(nil, mockColumn))
+
+	column := &attributedColumn{ColumnDefinition: mockColumn, attributes: columnAttributes{collation: "und-x-icu"}}
+	mockColumn.EXPECT().IsChange().Return(false).Once()
+	s.Equal(` collate "und-x-icu"`, s.grammar.ModifyCollation(nil, column))
+
+	mockColumn.EXPECT().IsChange().Return(true).Once()
+	s.Empty(s.grammar.ModifyCollation(nil, column))
+
+	column = &attributedColumn{ColumnDefinition: mockColumn, attributes: columnAttributes{collation: "en_US.utf8"}}
+	mockColumn.EXPECT().IsChange().Return(false).Once()
+	s.Equal(` collate "en_US.utf8"`, s.grammar.ModifyCollation(nil, column))
+}
+
+func (s *GrammarSuite) TestModifyCompression() {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	s.Empty(s.grammar.ModifyCompression(nil, mockColumn))
+
+	column := &attributedColumn{ColumnDefinition: mockColumn, attributes: columnAttributes{compression: "lz4"}}
+	mockColumn.EXPECT().IsChange().Return(false).Once()
+	s.Equal(" compression lz4", s.grammar.ModifyCompression(nil, column))
+
+	mockColumn.EXPECT().IsChange().Return(true).Once()
+	s.Equal(" set compression lz4", s.grammar.ModifyCompression(nil, column))
+}
+
 func (s *GrammarSuite) TestModifyDefault() {
 	var (
 		mockBlueprint *mocksdriver.Blueprint
@@ -775,6 +1889,22 @@ func (s *GrammarSuite) TestModifyDefault() {
 			},
 			expectSql: " set default 'goravel'",
 		},
+		{
+			name: "without change and default is an empty slice",
+			setup: func() {
+				mockColumn.EXPECT().IsChange().Return(false).Once()
+				mockColumn.EXPECT().GetDefault().Return([]string{}).Twice()
+			},
+			expectSql: " default '{}'",
+		},
+		{
+			name: "without change and default is a slice",
+			setup: func() {
+				mockColumn.EXPECT().IsChange().Return(false).Once()
+				mockColumn.EXPECT().GetDefault().Return([]any{"it's", `a "b"`, 1, nil, []int{2, 3}}).Twice()
+			},
+			expectSql: ` default '{"it''s","a \"b\"",1,NULL,{2,3}}'`,
+		},
 	}
 
 	for _, test := range tests {
@@ -946,6 +2076,18 @@ func (s *GrammarSuite) TestModifyIncrement() {
 	s.Equal(" primary key", s.grammar.ModifyIncrement(mockBlueprint, mockColumn))
 }
 
+func (s *GrammarSuite) TestModifyStorage() {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	s.Empty(s.grammar.ModifyStorage(nil, mockColumn))
+
+	column := &attributedColumn{ColumnDefinition: mockColumn, attributes: columnAttributes{storage: "external"}}
+	mockColumn.EXPECT().IsChange().Return(false).Once()
+	s.Equal(" storage external", s.grammar.ModifyStorage(nil, column))
+
+	mockColumn.EXPECT().IsChange().Return(true).Once()
+	s.Equal(" set storage external", s.grammar.ModifyStorage(nil, column))
+}
+
 func (s *GrammarSuite) TestTableComment() {
 	mockBlueprint := mocksdriver.NewBlueprint(s.T())
 	mockBlueprint.EXPECT().GetTableName().Return("users").Once()
@@ -955,6 +2097,17 @@ func (s *GrammarSuite) TestTableComment() {
 	}))
 }
 
+func (s *GrammarSuite) TestTypeBit() {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	mockColumn.EXPECT().GetLength().Return(0).Once()
+
+	s.Equal("bit", s.grammar.TypeBit(mockColumn))
+
+	mockColumn.EXPECT().GetLength().Return(8).Once()
+
+	s.Equal("bit(8)", s.grammar.TypeBit(mockColumn))
+}
+
 func (s *GrammarSuite) TestTypeBigInteger() {
 	mockColumn1 := mocksdriver.NewColumnDefinition(s.T())
 	mockColumn1.EXPECT().GetAutoIncrement().Return(true).Once()
@@ -1010,6 +2163,17 @@ func (s *GrammarSuite) TestTypeInteger() {
 	s.Equal("integer", s.grammar.TypeInteger(mockColumn2))
 }
 
+func (s *GrammarSuite) TestTypeInterval() {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	mockColumn.EXPECT().GetPrecision().Return(0).Once()
+
+	s.Equal("interval", s.grammar.TypeInterval(mockColumn))
+
+	mockColumn.EXPECT().GetPrecision().Return(3).Once()
+
+	s.Equal("interval(3)", s.grammar.TypeInterval(mockColumn))
+}
+
 func (s *GrammarSuite) TestTypeString() {
 	mockColumn1 := mocksdriver.NewColumnDefinition(s.T())
 	mockColumn1.EXPECT().GetLength().Return(100).Once()
@@ -1043,6 +2207,36 @@ func (s *GrammarSuite) TestTypeUuid() {
 	s.Equal("uuid", s.grammar.TypeUuid(mockColumn))
 }
 
+func (s *GrammarSuite) TestTypeVarBit() {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	mockColumn.EXPECT().GetLength().Return(0).Once()
+
+	s.Equal("varbit", s.grammar.TypeVarBit(mockColumn))
+
+	mockColumn.EXPECT().GetLength().Return(64).Once()
+
+	s.Equal("varbit(64)", s.grammar.TypeVarBit(mockColumn))
+}
+
+func (s *GrammarSuite) TestTypeVector() {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	mockColumn.EXPECT().GetLength().Return(0).Once()
+
+	s.Equal("vector", s.grammar.TypeVector(mockColumn))
+
+	mockColumn.EXPECT().GetLength().Return(1536).Once()
+
+	s.Equal("vector(1536)", s.grammar.TypeVector(mockColumn))
+
+	mockColumn.EXPECT().GetLength().Return(3072).Once()
+
+	s.Equal("halfvec(3072)", s.grammar.TypeHalfVector(mockColumn))
+
+	mockColumn.EXPECT().GetLength().Return(30000).Once()
+
+	s.Equal("sparsevec(30000)", s.grammar.TypeSparseVector(mockColumn))
+}
+
 func TestParseSchemaAndTable(t *testing.T) {
 	tests := []struct {
 		reference      string
@@ -1064,3 +2258,21 @@ func TestParseSchemaAndTable(t *testing.T) {
 		assert.Equal(t, test.expectedError, err)
 	}
 }
+
+func (s *GrammarSuite) mockCreateBlueprint() *mocksdriver.Blueprint {
+	mockColumn := mocksdriver.NewColumnDefinition(s.T())
+	mockBlueprint := mocksdriver.NewBlueprint(s.T())
+
+	mockBlueprint.EXPECT().GetTableName().Return("events").Once()
+	mockBlueprint.EXPECT().GetAddedColumns().Return([]contractsdriver.ColumnDefinition{mockColumn}).Once()
+	mockBlueprint.EXPECT().HasCommand("primary").Return(false).Once()
+	mockColumn.EXPECT().GetName().Return("name").Once()
+	mockColumn.EXPECT().GetType().Return("string").Twice()
+	mockColumn.EXPECT().GetLength().Return(100).Once()
+	mockColumn.EXPECT().GetDefault().Return(nil).Once()
+	mockColumn.EXPECT().GetNullable().Return(true).Once()
+	mockColumn.EXPECT().IsChange().Return(false).Times(4)
+	mockColumn.EXPECT().IsSetGeneratedAs().Return(false).Twice()
+
+	return mockBlueprint
+}
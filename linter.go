@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/driver"
+	"github.com/goravel/framework/contracts/log"
+	"github.com/goravel/framework/database/schema"
+
+	"github.com/goravel/postgres/contracts"
+)
+
+const (
+	LintLevelError   = "error"
+	LintLevelOff     = "off"
+	LintLevelWarning = "warning"
+
+	LintRuleChangeColumnType    = "change_column_type"
+	LintRuleNonConcurrentIndex  = "non_concurrent_index"
+	LintRuleRenameColumn        = "rename_column"
+	LintRuleValidatedForeignKey = "validated_foreign_key"
+	LintRuleVolatileDefault     = "volatile_default"
+)
+
+const (
+	lintSuggestionNewColumn      = "add a new column, backfill it in batches and switch the code to it before dropping the old one"
+	lintSuggestionSeparateCommit = "run it in a separate migration, outside of a transaction"
+)
+
+var volatileFunctionRegex = regexp.MustCompile(`(?i)\b(clock_timestamp|gen_random_uuid|nextval|random|statement_timestamp|timeofday|txid_current|uuid_generate_v\w+)\s*\(`)
+
+// LintIssue is a schema change of a Blueprint that blocks reads or writes of the table while it's applied.
+type LintIssue struct {
+	Level      string
+	Rule       string
+	Table      string
+	Message    string
+	Suggestion string
+}
+
+func (r LintIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s, %s", r.Level, r.Rule, r.Message, r.Suggestion)
+}
+
+// Linter checks the commands of a Blueprint for the schema changes that take an ACCESS EXCLUSIVE lock, rewrite
+// the table or scan it while blocking writes. The level of each rule can be set for a connection via
+// database.connections.%s.lint, which is a map[string]string from the rule to error, warning or off. When it's
+// set, the Grammar lints each Blueprint before any of its statements runs: the warning-level issues are logged,
+// and the first statement is replaced with one that raises the error-level issues, so the migrations fail, e.g.
+// in CI, before they block the tables.
+type Linter struct {
+	grammar *Grammar
+	log     log.Log
+	levels  map[string]string
+}
+
+func NewLinter(grammar *Grammar, log log.Log, levels map[string]string) *Linter {
+	return &Linter{
+		grammar: grammar,
+		log:     log,
+		levels:  levels,
+	}
+}
+
+// Lint returns the issues of a Blueprint, the error is not nil when any of them is at the error level.
+func (r *Linter) Lint(blueprint driver.Blueprint) ([]LintIssue, error) {
+	// The changes of a table that is created in the same migration can't block anyone.
+	if blueprint.HasCommand(schema.CommandCreate) {
+		return nil, nil
+	}
+
+	var issues []LintIssue
+	for _, command := range blueprint.GetCommands() {
+		issues = append(issues, r.commandIssues(blueprint, command)...)
+	}
+
+	return issues, r.error(blueprint, issues)
+}
+
+func (r *Linter) commandIssues(blueprint driver.Blueprint, command *driver.Command) []LintIssue {
+	var issues []LintIssue
+	for _, issue := range r.lintCommand(blueprint, command) {
+		issue.Level = r.level(issue.Rule)
+		if issue.Level == LintLevelOff {
+			continue
+		}
+
+		issue.Table = blueprint.GetTableName()
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+func (r *Linter) error(blueprint driver.Blueprint, issues []LintIssue) error {
+	var errors []string
+	for _, issue := range issues {
+		if issue.Level == LintLevelError {
+			errors = append(errors, issue.String())
+		}
+	}
+	if len(errors) > 0 {
+		return MigrationLintFailed.Args(blueprint.GetTableName(), strings.Join(errors, "\n"))
+	}
+
+	return nil
+}
+
+func (r *Linter) warn(issues []LintIssue) {
+	if r.log == nil {
+		return
+	}
+
+	for _, issue := range issues {
+		if issue.Level == LintLevelWarning {
+			r.log.Warningf("[%s] the migration of table %s has a blocking schema change: %s", Name, issue.Table, issue)
+		}
+	}
+}
+
+func (r *Linter) level(rule string) string {
+	if level, ok := r.levels[rule]; ok && slices.Contains([]string{LintLevelError, LintLevelOff, LintLevelWarning}, level) {
+		return level
+	}
+
+	return LintLevelWarning
+}
+
+func (r *Linter) lintCommand(blueprint driver.Blueprint, command *driver.Command) []LintIssue {
+	switch command.Name {
+	case schema.CommandAdd:
+		if command.Column.IsChange() {
+			return []LintIssue{{
+				Rule:       LintRuleChangeColumnType,
+				Message:    fmt.Sprintf("changing column %s takes an ACCESS EXCLUSIVE lock and may rewrite the table", command.Column.GetName()),
+				Suggestion: "check whether Grammar.CompileAlterColumnType reports a rewrite, or " + lintSuggestionNewColumn,
+			}}
+		}
+		if r.hasVolatileDefault(command.Column) {
+			return []LintIssue{{
+				Rule:       LintRuleVolatileDefault,
+				Message:    fmt.Sprintf("adding column %s with a volatile default rewrites the table under an ACCESS EXCLUSIVE lock", command.Column.GetName()),
+				Suggestion: "add the column without a default, set the default in a separate statement and backfill the existing rows in batches",
+			}}
+		}
+	case schema.CommandIndex, schema.CommandFullText:
+		var sql string
+		if command.Name == schema.CommandIndex {
+			sql = r.grammar.compileIndex(blueprint, command)
+		} else {
+			sql = r.grammar.compileFullText(blueprint, command)
+		}
+
+		return []LintIssue{{
+			Rule:       LintRuleNonConcurrentIndex,
+			Message:    fmt.Sprintf("creating index %s blocks writes to the table until the index is built", command.Index),
+			Suggestion: fmt.Sprintf("create the index concurrently and %s: %s", lintSuggestionSeparateCommit, strings.Replace(sql, "create index", "create index concurrently", 1)),
+		}}
+	case schema.CommandPrimary, schema.CommandUnique:
+		constraint := "unique"
+		if command.Name == schema.CommandPrimary {
+			constraint = "primary key"
+		}
+
+		index := command.Index
+		if index == "" {
+			_, table, _ := parseSchemaAndTable(blueprint.GetTableName(), "")
+			index = r.grammar.prefix + table + "_pkey"
+		}
+
+		return []LintIssue{{
+			Rule:    LintRuleNonConcurrentIndex,
+			Message: fmt.Sprintf("adding the %s constraint %s blocks writes to the table until its index is built", constraint, index),
+			Suggestion: fmt.Sprintf("create the unique index concurrently and %s, then add the constraint using it: create unique index concurrently %s on %s (%s); alter table %s add constraint %s %s using index %s",
+				lintSuggestionSeparateCommit,
+				r.grammar.wrap.Column(index),
+				r.grammar.wrap.Table(blueprint.GetTableName()),
+				r.grammar.wrap.Columnize(command.Columns),
+				r.grammar.wrap.Table(blueprint.GetTableName()),
+				r.grammar.wrap.Column(index),
+				constraint,
+				r.grammar.wrap.Column(index),
+			),
+		}}
+	case schema.CommandForeign:
+		// The foreign keys are added NOT VALID for the connection already.
+		if r.grammar.foreignKeyOptions.NotValid {
+			return nil
+		}
+
+		foreignKey := contracts.ForeignKey{
+			Table:              blueprint.GetTableName(),
+			Name:               command.Index,
+			Columns:            command.Columns,
+			On:                 command.On,
+			References:         command.References,
+			OnDelete:           command.OnDelete,
+			OnUpdate:           command.OnUpdate,
+			Deferrable:         command.Deferrable,
+			InitiallyImmediate: command.InitiallyImmediate,
+			NotValid:           true,
+		}
+
+		return []LintIssue{{
+			Rule:    LintRuleValidatedForeignKey,
+			Message: fmt.Sprintf("adding foreign key %s scans the table while blocking writes to it and to %s", command.Index, command.On),
+			Suggestion: fmt.Sprintf("add the foreign key not valid and validate it in a separate migration: %s; %s",
+				r.grammar.CompileAddForeignKey(foreignKey),
+				r.grammar.CompileValidateConstraint(foreignKey.Table, foreignKey.Name),
+			),
+		}}
+	case schema.CommandRenameColumn:
+		return []LintIssue{{
+			Rule:       LintRuleRenameColumn,
+			Message:    fmt.Sprintf("renaming column %s to %s breaks the running code that still uses %s", command.From, command.To, command.From),
+			Suggestion: lintSuggestionNewColumn,
+		}}
+	}
+
+	return nil
+}
+
+// hasVolatileDefault reports whether the default value of a column must be computed for each existing row,
+// which rewrites the table, e.g. the sequences of serial and identity columns or gen_random_uuid().
+func (r *Linter) hasVolatileDefault(column driver.ColumnDefinition) bool {
+	if column.IsSetGeneratedAs() {
+		return true
+	}
+	if column.GetAutoIncrement() && slices.Contains(r.grammar.serials, column.GetType()) {
+		return true
+	}
+	if expression, ok := column.GetDefault().(schema.Expression); ok {
+		return volatileFunctionRegex.MatchString(string(expression))
+	}
+
+	return false
+}
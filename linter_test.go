@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goravel/framework/database/schema"
+	"github.com/goravel/framework/errors"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goravel/postgres/contracts"
+)
+
+type LinterTestSuite struct {
+	suite.Suite
+	linter *Linter
+}
+
+func TestLinterTestSuite(t *testing.T) {
+	suite.Run(t, new(LinterTestSuite))
+}
+
+func (s *LinterTestSuite) SetupTest() {
+	s.linter = NewLinter(NewGrammar("goravel_"), nil, nil)
+}
+
+func (s *LinterTestSuite) TestLint() {
+	tests := []struct {
+		name        string
+		setup       func(blueprint *schema.Blueprint)
+		expectRules []string
+		expectText  string
+	}{
+		{
+			name: "created table",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.Create()
+				blueprint.ID()
+				blueprint.String("email")
+				blueprint.Unique("email")
+			},
+		},
+		{
+			name: "column with a stable default",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.Timestamp("verified_at").Default(schema.Expression("now()"))
+				blueprint.String("name").Default("goravel")
+			},
+		},
+		{
+			name: "column with a volatile default",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.Uuid("token").Default(schema.Expression("gen_random_uuid()"))
+			},
+			expectRules: []string{LintRuleVolatileDefault},
+			expectText:  "adding column token with a volatile default",
+		},
+		{
+			name: "serial column",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.BigIncrements("number")
+			},
+			expectRules: []string{LintRuleVolatileDefault},
+		},
+		{
+			name: "changed column",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.BigInteger("age").Change()
+			},
+			expectRules: []string{LintRuleChangeColumnType},
+		},
+		{
+			name: "index",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.Index("email")
+			},
+			expectRules: []string{LintRuleNonConcurrentIndex},
+			expectText:  `create index concurrently "goravel_users_email_index" on "goravel_users" ("email")`,
+		},
+		{
+			name: "unique",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.Unique("email")
+			},
+			expectRules: []string{LintRuleNonConcurrentIndex},
+			expectText:  `alter table "goravel_users" add constraint "goravel_users_email_unique" unique using index "goravel_users_email_unique"`,
+		},
+		{
+			name: "foreign key",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.Foreign("role_id").References("id").On("roles")
+			},
+			expectRules: []string{LintRuleValidatedForeignKey},
+			expectText:  `references "goravel_roles" ("id") not valid; alter table "goravel_users" validate constraint "goravel_users_role_id_foreign"`,
+		},
+		{
+			name: "renamed column",
+			setup: func(blueprint *schema.Blueprint) {
+				blueprint.RenameColumn("name", "full_name")
+			},
+			expectRules: []string{LintRuleRenameColumn},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			blueprint := schema.NewBlueprint(nil, "goravel_", "users")
+			test.setup(blueprint)
+
+			issues, err := s.linter.Lint(blueprint)
+			s.NoError(err)
+
+			var rules []string
+			for _, issue := range issues {
+				s.Equal(LintLevelWarning, issue.Level)
+				s.Equal("users", issue.Table)
+				rules = append(rules, issue.Rule)
+			}
+			s.Equal(test.expectRules, rules)
+			if test.expectText != "" {
+				s.Contains(issues[0].String(), test.expectText)
+			}
+		})
+	}
+}
+
+func (s *LinterTestSuite) TestLintNotValidForeignKeys() {
+	grammar := NewGrammar("goravel_")
+	grammar.foreignKeyOptions = contracts.ForeignKeyOptions{NotValid: true}
+
+	blueprint := schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.Foreign("role_id").References("id").On("roles")
+	issues, err := NewLinter(grammar, nil, nil).Lint(blueprint)
+	s.NoError(err)
+	s.Empty(issues)
+}
+
+func (s *LinterTestSuite) TestLintLevels() {
+	linter := NewLinter(NewGrammar("goravel_"), nil, map[string]string{
+		LintRuleNonConcurrentIndex: LintLevelError,
+		LintRuleRenameColumn:       LintLevelOff,
+	})
+
+	blueprint := schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.RenameColumn("name", "full_name")
+	issues, err := linter.Lint(blueprint)
+	s.NoError(err)
+	s.Empty(issues)
+
+	blueprint = schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.Index("email")
+	issues, err = linter.Lint(blueprint)
+	s.True(errors.Is(err, MigrationLintFailed))
+	s.Len(issues, 1)
+	s.Equal(LintLevelError, issues[0].Level)
+}
+
+func (s *LinterTestSuite) TestGrammarRaisesErrors() {
+	grammar := NewGrammar("goravel_")
+	grammar.linter = NewLinter(grammar, nil, map[string]string{
+		LintRuleNonConcurrentIndex: LintLevelError,
+		LintRuleRenameColumn:       LintLevelError,
+	})
+
+	blueprint := schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.Index("email")
+	blueprint.Foreign("role_id").References("id").On("roles")
+	commands := blueprint.GetCommands()
+
+	sql := grammar.CompileIndex(blueprint, commands[0])
+	s.True(strings.HasPrefix(sql, "do $do$ begin raise exception '%', 'the migration of table users has blocking schema changes:"))
+	s.Contains(sql, `[error] non_concurrent_index: creating index goravel_users_email_index blocks writes`)
+	s.Equal(`alter table "goravel_users" add constraint "goravel_users_role_id_foreign" foreign key ("role_id") references "goravel_roles" ("id")`,
+		grammar.CompileForeign(blueprint, commands[1]))
+
+	blueprint = schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.RenameColumn("name", "full_name")
+	_, err := grammar.CompileRenameColumn(blueprint, blueprint.GetCommands()[0], nil)
+	s.True(errors.Is(err, MigrationLintFailed))
+
+	blueprint = schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.Create()
+	blueprint.Index("email")
+	s.Equal(`create index "goravel_users_email_index" on "goravel_users" ("email")`, grammar.CompileIndex(blueprint, blueprint.GetCommands()[1]))
+}
+
+func (s *LinterTestSuite) TestGrammarLintsTheWholeBlueprint() {
+	mockLog := mockslog.NewLog(s.T())
+	grammar := NewGrammar("goravel_")
+	grammar.linter = NewLinter(grammar, mockLog, map[string]string{
+		LintRuleNonConcurrentIndex: LintLevelError,
+	})
+
+	// The first statement raises the error of the index that is created after it, so nothing is applied.
+	blueprint := schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.DropColumn("legacy")
+	blueprint.Index("email")
+	commands := blueprint.GetCommands()
+
+	sql := grammar.CompileDropColumn(blueprint, commands[0])
+	s.Len(sql, 1)
+	s.True(strings.HasPrefix(sql[0], "do $do$ begin raise exception '%', 'the migration of table users has blocking schema changes:"))
+	s.Contains(sql[0], `[error] non_concurrent_index: creating index goravel_users_email_index blocks writes`)
+	s.Equal(`create index "goravel_users_email_index" on "goravel_users" ("email")`, grammar.CompileIndex(blueprint, commands[1]))
+
+	blueprint = schema.NewBlueprint(nil, "goravel_", "users")
+	blueprint.RenameColumn("name", "full_name")
+	mockLog.EXPECT().Warningf("[%s] the migration of table %s has a blocking schema change: %s", Name, "users", mock.Anything).Once()
+	statement, err := grammar.CompileRenameColumn(blueprint, blueprint.GetCommands()[0], nil)
+	s.NoError(err)
+	s.Equal(`alter table "goravel_users" rename column "name" to "full_name"`, statement)
+}
@@ -6,11 +6,15 @@ import (
 	"github.com/goravel/framework/contracts/config"
 	"github.com/goravel/framework/contracts/database"
 	"github.com/goravel/framework/contracts/database/driver"
+	"github.com/goravel/framework/contracts/database/orm"
+	contractsschema "github.com/goravel/framework/contracts/database/schema"
 	"github.com/goravel/framework/contracts/log"
 	"github.com/goravel/framework/contracts/process"
 	"github.com/goravel/framework/contracts/testing/docker"
+	"github.com/goravel/framework/database/schema"
 	"github.com/goravel/framework/errors"
 	"github.com/goravel/postgres/contracts"
+	"github.com/spf13/cast"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -44,8 +48,54 @@ func (r *Postgres) Docker() (docker.DatabaseDriver, error) {
 	return NewDocker(r.config, r.process, writers[0].Database, writers[0].Username, writers[0].Password), nil
 }
 
+// CreateTable creates a table with the options in a migration, e.g. an unlogged staging table, they take the place
+// of the ones of database.connections.%s.table_options for the table. The query runs the statements, e.g.
+// facades.Orm().Query(), in a transaction unless it's in one already.
+func (r *Postgres) CreateTable(query orm.Query, table string, options contracts.TableOptions, callback func(table contractsschema.Blueprint)) error {
+	grammar := r.grammar()
+	if grammar.tableOptions == nil {
+		grammar.tableOptions = make(map[string]contracts.TableOptions)
+	}
+	grammar.tableOptions[table] = options
+
+	blueprint := schema.NewBlueprint(nil, grammar.prefix, table)
+	blueprint.Create()
+	callback(blueprint)
+
+	if query.InTransaction() {
+		return blueprint.Build(query, grammar)
+	}
+
+	tx, err := query.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	if err := blueprint.Build(tx, grammar); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+// Differ returns the differ of the connection, which compares the snapshots of Dumper.Snapshot and ReadSnapshot.
+func (r *Postgres) Differ() *Differ {
+	return NewDiffer(r.grammar())
+}
+
+// Dumper returns the schema dumper of the connection, the query runs the introspection queries, e.g. facades.Orm().Query().
+func (r *Postgres) Dumper(query orm.Query) *Dumper {
+	return NewDumper(r.grammar(), query, r.config.Config().GetString("database.migrations.table", "migrations"))
+}
+
 func (r *Postgres) Grammar() driver.Grammar {
-	return NewGrammar(r.config.Writers()[0].Prefix)
+	return r.grammar()
+}
+
+// Linter returns the linter of the migrations with the rule levels of the connection.
+func (r *Postgres) Linter() *Linter {
+	levels := cast.ToStringMapString(r.config.Config().Get(fmt.Sprintf("database.connections.%s.lint", r.config.Connection())))
+
+	return NewLinter(r.grammar(), r.log, levels)
 }
 
 func (r *Postgres) Pool() database.Pool {
@@ -66,7 +116,7 @@ func (r *Postgres) fullConfigsToConfigs(fullConfigs []contracts.FullConfig) []da
 			Connection:   fullConfig.Connection,
 			Dsn:          fullConfig.Dsn,
 			Database:     fullConfig.Database,
-			Dialector:    fullConfigToDialector(fullConfig),
+			Dialector:    r.dialector(fullConfig),
 			Driver:       Name,
 			Host:         fullConfig.Host,
 			NameReplacer: fullConfig.NameReplacer,
@@ -85,6 +135,77 @@ func (r *Postgres) fullConfigsToConfigs(fullConfigs []contracts.FullConfig) []da
 	return configs
 }
 
+// dialector wraps the dialector of a config with Dialector when the connection sets a lock_timeout or a
+// statement_timeout for the schema statements, lock_timeout_attempts is the number of times to run them.
+func (r *Postgres) dialector(fullConfig contracts.FullConfig) gorm.Dialector {
+	dialector := fullConfigToDialector(fullConfig)
+	if dialector == nil {
+		return nil
+	}
+
+	lockTimeout := r.config.Config().GetString(fmt.Sprintf("database.connections.%s.lock_timeout", r.config.Connection()))
+	statementTimeout := r.config.Config().GetString(fmt.Sprintf("database.connections.%s.statement_timeout", r.config.Connection()))
+	if lockTimeout == "" && statementTimeout == "" {
+		return dialector
+	}
+
+	return NewDialector(
+		dialector.(*postgres.Dialector),
+		r.log,
+		lockTimeout,
+		statementTimeout,
+		r.config.Config().GetInt(fmt.Sprintf("database.connections.%s.lock_timeout_attempts", r.config.Connection()), 5),
+	)
+}
+
+func (r *Postgres) grammar() *Grammar {
+	grammar := NewGrammar(r.config.Writers()[0].Prefix)
+	if tableOptions := r.config.Config().Get(fmt.Sprintf("database.connections.%s.table_options", r.config.Connection())); tableOptions != nil {
+		grammar.tableOptions = decodeTableOptions(tableOptions)
+	}
+	if foreignKeyOptions, ok := r.config.Config().Get(fmt.Sprintf("database.connections.%s.foreign_keys", r.config.Connection())).(contracts.ForeignKeyOptions); ok {
+		grammar.foreignKeyOptions = foreignKeyOptions
+	}
+	// The migrations fail on the error-level issues of the linter when the lint levels are set.
+	if levels := r.config.Config().Get(fmt.Sprintf("database.connections.%s.lint", r.config.Connection())); levels != nil {
+		grammar.linter = NewLinter(grammar, r.log, cast.ToStringMapString(levels))
+	}
+	// The idempotent mode skips the objects that already exist or don't exist anymore, so a migration that failed halfway can be run again.
+	grammar.idempotent = r.config.Config().GetBool(fmt.Sprintf("database.connections.%s.idempotent", r.config.Connection()))
+
+	return grammar
+}
+
+// decodeTableOptions reads database.connections.%s.table_options, which is a map[string]contracts.TableOptions or,
+// e.g. when it's read from the environment or a file, a map[string]any of maps with the snake case names of the
+// options: if_not_exists, unlogged, temporary, on_commit, inherits, with and tablespace.
+func decodeTableOptions(value any) map[string]contracts.TableOptions {
+	if tableOptions, ok := value.(map[string]contracts.TableOptions); ok {
+		return tableOptions
+	}
+
+	tableOptions := make(map[string]contracts.TableOptions)
+	for table, value := range cast.ToStringMap(value) {
+		if options, ok := value.(contracts.TableOptions); ok {
+			tableOptions[table] = options
+			continue
+		}
+
+		options := cast.ToStringMap(value)
+		tableOptions[table] = contracts.TableOptions{
+			IfNotExists: cast.ToBool(options["if_not_exists"]),
+			Unlogged:    cast.ToBool(options["unlogged"]),
+			Temporary:   cast.ToBool(options["temporary"]),
+			OnCommit:    cast.ToString(options["on_commit"]),
+			Inherits:    cast.ToStringSlice(options["inherits"]),
+			With:        cast.ToStringMap(options["with"]),
+			Tablespace:  cast.ToString(options["tablespace"]),
+		}
+	}
+
+	return tableOptions
+}
+
 func dsn(fullConfig contracts.FullConfig) string {
 	if fullConfig.Dsn != "" {
 		return fullConfig.Dsn
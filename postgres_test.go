@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"fmt"
+	"testing"
+
+	contractsschema "github.com/goravel/framework/contracts/database/schema"
+	mocksconfig "github.com/goravel/framework/mocks/config"
+	mocksorm "github.com/goravel/framework/mocks/database/orm"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goravel/postgres/contracts"
+)
+
+type PostgresTestSuite struct {
+	suite.Suite
+	connection string
+	mockConfig *mocksconfig.Config
+	postgres   *Postgres
+}
+
+func TestPostgresTestSuite(t *testing.T) {
+	suite.Run(t, &PostgresTestSuite{
+		connection: "postgres",
+	})
+}
+
+func (s *PostgresTestSuite) SetupTest() {
+	s.mockConfig = mocksconfig.NewConfig(s.T())
+	s.postgres = NewPostgres(s.mockConfig, nil, nil, s.connection)
+}
+
+func (s *PostgresTestSuite) TestGrammar() {
+	s.mockWriters()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.table_options", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.foreign_keys", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.lint", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().GetBool(fmt.Sprintf("database.connections.%s.idempotent", s.connection)).Return(false).Once()
+
+	grammar := s.postgres.grammar()
+	s.Equal("goravel_", grammar.prefix)
+	s.Nil(grammar.linter)
+}
+
+func (s *PostgresTestSuite) TestGrammarWithLintLevels() {
+	s.mockWriters()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.table_options", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.foreign_keys", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.lint", s.connection)).Return(map[string]any{
+		LintRuleNonConcurrentIndex: LintLevelError,
+		LintRuleRenameColumn:       LintLevelOff,
+	}).Once()
+	s.mockConfig.EXPECT().GetBool(fmt.Sprintf("database.connections.%s.idempotent", s.connection)).Return(false).Once()
+
+	grammar := s.postgres.grammar()
+	s.Require().NotNil(grammar.linter)
+	s.Equal(map[string]string{
+		LintRuleNonConcurrentIndex: LintLevelError,
+		LintRuleRenameColumn:       LintLevelOff,
+	}, grammar.linter.levels)
+}
+
+func (s *PostgresTestSuite) TestGrammarWithTableOptions() {
+	s.mockWriters()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.table_options", s.connection)).Return(map[string]any{
+		"logs": contracts.TableOptions{Unlogged: true},
+		"events": map[string]any{
+			"if_not_exists": true,
+			"inherits":      []any{"logs"},
+			"with":          map[string]any{"fillfactor": 70},
+			"tablespace":    "fast",
+		},
+	}).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.foreign_keys", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.lint", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().GetBool(fmt.Sprintf("database.connections.%s.idempotent", s.connection)).Return(false).Once()
+
+	grammar := s.postgres.grammar()
+	s.Equal(contracts.TableOptions{Unlogged: true}, grammar.tableOptions["logs"])
+	s.Equal(contracts.TableOptions{
+		IfNotExists: true,
+		Inherits:    []string{"logs"},
+		With:        map[string]any{"fillfactor": 70},
+		Tablespace:  "fast",
+	}, grammar.tableOptions["events"])
+}
+
+func (s *PostgresTestSuite) TestCreateTable() {
+	s.mockWriters()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.table_options", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.foreign_keys", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.lint", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().GetBool(fmt.Sprintf("database.connections.%s.idempotent", s.connection)).Return(false).Once()
+
+	mockQuery := mocksorm.NewQuery(s.T())
+	mockTx := mocksorm.NewQuery(s.T())
+	mockQuery.EXPECT().InTransaction().Return(false).Once()
+	mockQuery.EXPECT().BeginTransaction().Return(mockTx, nil).Once()
+	mockTx.EXPECT().Exec(`create unlogged table "goravel_staging" ("id" bigserial primary key not null, "payload" jsonb not null) with (autovacuum_enabled = false)`).Return(nil, nil).Once()
+	mockTx.EXPECT().Exec(`create index "goravel_staging_payload_index" on "goravel_staging" using gin ("payload")`).Return(nil, nil).Once()
+	mockTx.EXPECT().Commit().Return(nil).Once()
+
+	s.NoError(s.postgres.CreateTable(mockQuery, "staging", contracts.TableOptions{
+		Unlogged: true,
+		With:     map[string]any{"autovacuum_enabled": false},
+	}, func(table contractsschema.Blueprint) {
+		table.ID()
+		table.Jsonb("payload")
+		table.Index("payload").Algorithm("gin")
+	}))
+}
+
+func (s *PostgresTestSuite) mockWriters() {
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.write", s.connection)).Return([]contracts.Config{
+		{Dsn: "dsn", Host: "localhost", Port: 5432, Database: "goravel", Username: "root", Password: "123123", Schema: "public"},
+	}).Once()
+	s.mockConfig.EXPECT().GetBool(fmt.Sprintf("database.connections.%s.no_lower_case", s.connection)).Return(false).Once()
+	s.mockConfig.EXPECT().GetString(fmt.Sprintf("database.connections.%s.prefix", s.connection)).Return("goravel_").Once()
+	s.mockConfig.EXPECT().GetBool(fmt.Sprintf("database.connections.%s.singular", s.connection)).Return(false).Once()
+	s.mockConfig.EXPECT().GetString(fmt.Sprintf("database.connections.%s.sslmode", s.connection)).Return("disable").Once()
+	s.mockConfig.EXPECT().Get(fmt.Sprintf("database.connections.%s.name_replacer", s.connection)).Return(nil).Once()
+	s.mockConfig.EXPECT().GetString(fmt.Sprintf("database.connections.%s.timezone", s.connection)).Return("UTC").Once()
+}
@@ -1,45 +1,410 @@
 package postgres
 
 import (
+	"encoding/json"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/goravel/framework/contracts/database/driver"
+	"github.com/goravel/framework/support/collect"
 	"github.com/goravel/framework/support/str"
 	"github.com/spf13/cast"
+
+	"github.com/goravel/postgres/contracts"
 )
 
 var _ driver.Processor = &Processor{}
 
+var (
+	// columnTypes maps the name of a PostgreSQL type to the column type that recreates it via the grammar.
+	columnTypes = map[string]string{
+		"bit":            "bit",
+		"bool":           "boolean",
+		"bpchar":         "char",
+		"bytea":          "binary",
+		"cidr":           "cidr",
+		"date":           "date",
+		"datemultirange": "dateMultiRange",
+		"daterange":      "dateRange",
+		"float8":         "double",
+		"geography":      "geography",
+		"geometry":       "geometry",
+		"halfvec":        "halfVector",
+		"inet":           "inet",
+		"int2":           "smallInteger",
+		"int4":           "integer",
+		"int4multirange": "int4MultiRange",
+		"int4range":      "int4Range",
+		"int8":           "bigInteger",
+		"int8multirange": "int8MultiRange",
+		"int8range":      "int8Range",
+		"interval":       "interval",
+		"json":           "json",
+		"jsonb":          "jsonb",
+		"macaddr":        "macAddress",
+		"macaddr8":       "macAddress8",
+		"money":          "money",
+		"numeric":        "decimal",
+		"nummultirange":  "numMultiRange",
+		"numrange":       "numRange",
+		"sparsevec":      "sparseVector",
+		"text":           "text",
+		"time":           "time",
+		"timestamp":      "timestamp",
+		"timestamptz":    "timestampTz",
+		"timetz":         "timeTz",
+		"tsmultirange":   "tsMultiRange",
+		"tsquery":        "tsQuery",
+		"tsrange":        "tsRange",
+		"tstzmultirange": "tsTzMultiRange",
+		"tstzrange":      "tsTzRange",
+		"tsvector":       "tsVector",
+		"uuid":           "uuid",
+		"varbit":         "varBit",
+		"varchar":        "string",
+		"vector":         "vector",
+		"xml":            "xml",
+	}
+	// columnTypesWithModifiers can only be recreated when the modifiers are explicit, the default
+	// modifiers of the grammar differ from the ones of PostgreSQL.
+	columnTypesWithModifiers = []string{"decimal", "time", "timeTz", "timestamp", "timestampTz"}
+	formatTypeModifierRegex  = regexp.MustCompile(`\((\d+(?:,\d+)?)\)`)
+)
+
 type Processor struct {
 }
 
+// Column is a driver.Column with the metadata that is specific to PostgreSQL.
+type Column struct {
+	driver.Column
+	// BaseType is the type a domain column is based on, e.g. character varying(255).
+	BaseType string
+	// ColumnType is the column type that can be passed to Blueprint.Column to recreate the column,
+	// e.g. string(255) for a character varying(255) column.
+	ColumnType string
+	// ElementType is the element type of an array column, e.g. integer for an integer[] column.
+	ElementType string
+	// Generated is stored or virtual for a generated column, Generation is its expression.
+	Generated  string
+	Generation string
+	// Identity is always or by default for an identity column.
+	Identity string
+	// Compression is the compression method of the column, e.g. lz4, it's empty for the default one.
+	Compression string
+	// Storage is the storage mode of the column when it isn't the one of its type, e.g. external.
+	Storage string
+	Array   bool
+	// Dimensions is the number of the declared dimensions of an array column.
+	Dimensions int
+	// Length, Precision and Scale are the explicit modifiers of the type, or of the base type of a domain,
+	// e.g. 255 for character varying(255), 10 and 2 for numeric(10,2), 3 for timestamp(3) with time zone.
+	Length    int
+	Precision int
+	Scale     int
+}
+
+// DBCheck is the result of Grammar.CompileChecks.
+type DBCheck struct {
+	Name       string
+	Definition string
+}
+
+// DBColumn is a driver.DBColumn with the columns of Grammar.CompileColumns that driver.DBColumn doesn't have.
+type DBColumn struct {
+	driver.DBColumn
+	BaseType    string
+	Compression string
+	Generated   string
+	Generation  string
+	Identity    string
+	Storage     string
+	Dimensions  int
+}
+
+// DBForeignKey is a driver.DBForeignKey with the columns of Grammar.CompileForeignKeys that driver.DBForeignKey doesn't have.
+type DBForeignKey struct {
+	driver.DBForeignKey
+	Match             string
+	Deferrable        bool
+	InitiallyDeferred bool
+}
+
+// DBIndex is a driver.DBIndex with the definition of the index, it's the result of Grammar.CompileIndexes.
+type DBIndex struct {
+	driver.DBIndex
+	Definition           string
+	ConstraintDefinition string
+}
+
+// DBPolicy is the result of Grammar.CompilePolicies.
+type DBPolicy struct {
+	Name      string
+	As        string
+	Command   string
+	Roles     string
+	Using     string
+	WithCheck string
+}
+
+// DBTrigger is the result of Grammar.CompileTriggers.
+type DBTrigger struct {
+	Name       string
+	Events     string
+	Timing     string
+	ForEach    string
+	When       string
+	Statement  string
+	Definition string
+}
+
+// ForeignKey is a driver.ForeignKey with the options that are specific to PostgreSQL.
+type ForeignKey struct {
+	driver.ForeignKey
+	// Match is full or partial, it's empty for simple, which is the default one.
+	Match             string
+	Deferrable        bool
+	InitiallyDeferred bool
+}
+
+// Index is a driver.Index with the statement that recreates it, e.g. CREATE INDEX name ON table USING gin (column).
+type Index struct {
+	driver.Index
+	Definition string
+	// ConstraintDefinition is the definition of the primary key, unique or exclusion constraint that the index
+	// belongs to, e.g. UNIQUE (email), it's empty for the indexes without a constraint.
+	ConstraintDefinition string
+}
+
+// Function is the result of Grammar.CompileFunctions.
+type Function struct {
+	Name       string
+	Schema     string
+	Arguments  string
+	Returns    string
+	Language   string
+	Volatility string
+	// Definition is the complete CREATE OR REPLACE FUNCTION or PROCEDURE statement of the function.
+	Definition string
+	// Signature is the name and the argument types of the function, e.g. public.add(integer,integer).
+	Signature string
+	// Dependencies is a JSON array of the signatures of the functions that the function uses.
+	Dependencies    string
+	Procedure       bool
+	SecurityDefiner bool
+	// UsesTables and UsesViews report whether the arguments, the result or the body of the function use
+	// the row type of a table or a view or the table or the view itself.
+	UsesTables bool
+	UsesViews  bool
+}
+
+// Policy describes a row level security policy of a table.
+type Policy struct {
+	Name      string
+	As        string
+	Command   string
+	Using     string
+	WithCheck string
+	Roles     []string
+}
+
+// Sequence is the result of Grammar.CompileSequences.
+type Sequence struct {
+	Name      string
+	Schema    string
+	Type      string
+	OwnedBy   string
+	Start     int64
+	Increment int64
+	MinValue  int64
+	MaxValue  int64
+	Cache     int64
+	LastValue *int64
+	Cycle     bool
+	// Identity reports whether the sequence belongs to an identity column, which creates it.
+	Identity bool
+}
+
+// TablePrivilege is the result of Grammar.CompileTablePrivileges.
+type TablePrivilege struct {
+	Grantee   string
+	Grantor   string
+	Privilege string
+	Grantable bool
+}
+
+// View is a driver.View that tells the materialized views apart, it's the result of Grammar.CompileViews.
+type View struct {
+	driver.View
+	// Dependencies is a JSON array of the schemas and the names of the views that the view selects from.
+	Dependencies string
+	// Indexes is a JSON array of the definitions of the indexes of a materialized view.
+	Indexes      string
+	Materialized bool
+}
+
+// DBType is a driver.Type with the columns of Grammar.CompileTypes that driver.Type doesn't have.
+type DBType struct {
+	driver.Type
+	// Attributes is a JSON array of the names, the types and the collations of the attributes of a composite type.
+	Attributes string
+	BaseType   string
+	Collation  string
+	// Constraints is a JSON array of the names and the definitions of the check constraints of a domain.
+	Constraints string
+	Default     string
+	// EnumLabels is a JSON array of the values of an enum in their order.
+	EnumLabels string
+	// Range is a JSON object of the options of a range type.
+	Range   string
+	NotNull bool
+}
+
+// Trigger describes a trigger of a table.
+type Trigger struct {
+	Name      string
+	Timing    string
+	ForEach   string
+	When      string
+	Statement string
+	// Events are insert, update, delete, truncate or update of the columns, e.g. update of "name", "email".
+	Events []string
+	// Definition is the statement that recreates the trigger, e.g. CREATE TRIGGER name BEFORE UPDATE ON table ...
+	Definition string
+}
+
+// Type is a driver.Type with the metadata that is specific to PostgreSQL.
+type Type struct {
+	driver.Type
+	// Attributes are the attributes of a composite type in their order.
+	Attributes []TypeAttribute
+	// BaseType is the type a domain is based on, e.g. character varying(255).
+	BaseType string
+	// Collation and Default are the ones of a domain when they are set.
+	Collation string
+	Default   string
+	// Checks are the check constraints of a domain.
+	Checks []contracts.Check
+	// Labels are the values of an enum in their order.
+	Labels []string
+	// Range is the subtype and the options of a range type, its name is empty.
+	Range   contracts.RangeType
+	NotNull bool
+}
+
+// TypeAttribute is an attribute of a composite type, the collation is empty when it's the one of its type.
+type TypeAttribute struct {
+	Name      string
+	Type      string
+	Collation string
+}
+
 func NewProcessor() *Processor {
 	return &Processor{}
 }
 
+func (r Processor) ProcessChecks(dbChecks []DBCheck) []contracts.Check {
+	var checks []contracts.Check
+	for _, dbCheck := range dbChecks {
+		checks = append(checks, contracts.Check{
+			Name:       dbCheck.Name,
+			Expression: checkExpression(dbCheck.Definition),
+			NotValid:   strings.HasSuffix(dbCheck.Definition, " NOT VALID"),
+		})
+	}
+
+	return checks
+}
+
 func (r Processor) ProcessColumns(dbColumns []driver.DBColumn) []driver.Column {
 	var columns []driver.Column
+	for _, column := range r.ProcessColumnDetails(collect.Map(dbColumns, func(dbColumn driver.DBColumn, _ int) DBColumn {
+		return DBColumn{DBColumn: dbColumn}
+	})) {
+		columns = append(columns, column.Column)
+	}
+
+	return columns
+}
+
+func (r Processor) ProcessColumnDetails(dbColumns []DBColumn) []Column {
+	var columns []Column
 	for _, dbColumn := range dbColumns {
-		var autoincrement bool
+		// The identity columns are incrementing as well, their sequence isn't in the default.
+		autoincrement := dbColumn.Autoincrement || dbColumn.Identity != ""
 		if str.Of(dbColumn.Default).StartsWith("nextval(") {
 			autoincrement = true
 		}
 
-		columns = append(columns, driver.Column{
-			Autoincrement: autoincrement,
-			Collation:     dbColumn.Collation,
-			Comment:       dbColumn.Comment,
-			Default:       dbColumn.Default,
-			Name:          dbColumn.Name,
-			Nullable:      cast.ToBool(dbColumn.Nullable),
-			Type:          dbColumn.Type,
-			TypeName:      dbColumn.TypeName,
+		// The name of an array type is the name of its element type with a leading underscore, e.g. _int4.
+		var elementType string
+		dimensions := dbColumn.Dimensions
+		isArray := strings.HasPrefix(dbColumn.TypeName, "_")
+		if isArray {
+			elementType = strings.TrimSuffix(dbColumn.Type, arraySuffix)
+			if elementType == dbColumn.Type {
+				elementType = strings.TrimPrefix(dbColumn.TypeName, "_")
+			}
+			// The dimensions aren't recorded for the columns that are created by e.g. CREATE TABLE AS.
+			dimensions = max(dimensions, 1)
+		}
+
+		ttype := dbColumn.Type
+		if dbColumn.BaseType != "" {
+			ttype = dbColumn.BaseType
+		}
+		length, precision, scale := typeModifiers(ttype)
+
+		columns = append(columns, Column{
+			Column: driver.Column{
+				Autoincrement: autoincrement,
+				Collation:     dbColumn.Collation,
+				Comment:       dbColumn.Comment,
+				Default:       dbColumn.Default,
+				Name:          dbColumn.Name,
+				Nullable:      cast.ToBool(dbColumn.Nullable),
+				Type:          dbColumn.Type,
+				TypeName:      dbColumn.TypeName,
+			},
+			Array:       isArray,
+			BaseType:    dbColumn.BaseType,
+			ColumnType:  columnType(dbColumn.TypeName, dbColumn.Type),
+			Compression: dbColumn.Compression,
+			Dimensions:  dimensions,
+			ElementType: elementType,
+			Generated:   dbColumn.Generated,
+			Generation:  dbColumn.Generation,
+			Identity:    dbColumn.Identity,
+			Length:      length,
+			Precision:   precision,
+			Scale:       scale,
+			Storage:     dbColumn.Storage,
 		})
 	}
 
 	return columns
 }
 
+// ProcessForeignKeyDetails processes the foreign keys of Grammar.CompileForeignKeys with their match types and deferrability.
+func (r Processor) ProcessForeignKeyDetails(dbForeignKeys []DBForeignKey) []ForeignKey {
+	match := map[string]string{
+		"f": "full",
+		"p": "partial",
+	}
+
+	var foreignKeys []ForeignKey
+	for _, dbForeignKey := range dbForeignKeys {
+		foreignKeys = append(foreignKeys, ForeignKey{
+			ForeignKey:        r.ProcessForeignKeys([]driver.DBForeignKey{dbForeignKey.DBForeignKey})[0],
+			Match:             match[dbForeignKey.Match],
+			Deferrable:        dbForeignKey.Deferrable,
+			InitiallyDeferred: dbForeignKey.InitiallyDeferred,
+		})
+	}
+
+	return foreignKeys
+}
+
 func (r Processor) ProcessForeignKeys(dbForeignKeys []driver.DBForeignKey) []driver.ForeignKey {
 	var foreignKeys []driver.ForeignKey
 
@@ -75,6 +440,35 @@ func (r Processor) ProcessForeignKeys(dbForeignKeys []driver.DBForeignKey) []dri
 	return foreignKeys
 }
 
+func (r Processor) ProcessFunctions(functions []Function) []Function {
+	volatility := map[string]string{
+		"i": "immutable",
+		"s": "stable",
+		"v": "volatile",
+	}
+
+	for i, function := range functions {
+		if v, ok := volatility[function.Volatility]; ok {
+			functions[i].Volatility = v
+		}
+	}
+
+	return functions
+}
+
+func (r Processor) ProcessIndexDetails(dbIndexes []DBIndex) []Index {
+	var indexes []Index
+	for _, dbIndex := range dbIndexes {
+		indexes = append(indexes, Index{
+			Index:                r.ProcessIndexes([]driver.DBIndex{dbIndex.DBIndex})[0],
+			Definition:           dbIndex.Definition,
+			ConstraintDefinition: dbIndex.ConstraintDefinition,
+		})
+	}
+
+	return indexes
+}
+
 func (r Processor) ProcessIndexes(dbIndexes []driver.DBIndex) []driver.Index {
 	var indexes []driver.Index
 	for _, dbIndex := range dbIndexes {
@@ -90,6 +484,91 @@ func (r Processor) ProcessIndexes(dbIndexes []driver.DBIndex) []driver.Index {
 	return indexes
 }
 
+func (r Processor) ProcessPolicies(dbPolicies []DBPolicy) []Policy {
+	var policies []Policy
+	for _, dbPolicy := range dbPolicies {
+		policies = append(policies, Policy{
+			Name:      dbPolicy.Name,
+			As:        strings.ToLower(dbPolicy.As),
+			Command:   strings.ToLower(dbPolicy.Command),
+			Roles:     strings.Split(dbPolicy.Roles, ","),
+			Using:     dbPolicy.Using,
+			WithCheck: dbPolicy.WithCheck,
+		})
+	}
+
+	return policies
+}
+
+func (r Processor) ProcessTriggers(dbTriggers []DBTrigger) []Trigger {
+	var triggers []Trigger
+	for _, dbTrigger := range dbTriggers {
+		triggers = append(triggers, Trigger{
+			Name:       dbTrigger.Name,
+			Events:     strings.Split(dbTrigger.Events, " or "),
+			Timing:     strings.ToLower(dbTrigger.Timing),
+			ForEach:    strings.ToLower(dbTrigger.ForEach),
+			When:       dbTrigger.When,
+			Statement:  dbTrigger.Statement,
+			Definition: dbTrigger.Definition,
+		})
+	}
+
+	return triggers
+}
+
+func (r Processor) ProcessTypeDetails(dbTypes []DBType) ([]Type, error) {
+	types := make([]driver.Type, len(dbTypes))
+	for i, dbType := range dbTypes {
+		types[i] = dbType.Type
+	}
+
+	var details []Type
+	for i, t := range r.ProcessTypes(types) {
+		var dbChecks []DBCheck
+		if dbTypes[i].Constraints != "" {
+			if err := json.Unmarshal([]byte(dbTypes[i].Constraints), &dbChecks); err != nil {
+				return nil, err
+			}
+		}
+
+		var labels []string
+		if dbTypes[i].EnumLabels != "" {
+			if err := json.Unmarshal([]byte(dbTypes[i].EnumLabels), &labels); err != nil {
+				return nil, err
+			}
+		}
+
+		var attributes []TypeAttribute
+		if dbTypes[i].Attributes != "" {
+			if err := json.Unmarshal([]byte(dbTypes[i].Attributes), &attributes); err != nil {
+				return nil, err
+			}
+		}
+
+		var rangeType contracts.RangeType
+		if dbTypes[i].Range != "" {
+			if err := json.Unmarshal([]byte(dbTypes[i].Range), &rangeType); err != nil {
+				return nil, err
+			}
+		}
+
+		details = append(details, Type{
+			Type:       t,
+			Attributes: attributes,
+			BaseType:   dbTypes[i].BaseType,
+			Checks:     r.ProcessChecks(dbChecks),
+			Collation:  dbTypes[i].Collation,
+			Default:    dbTypes[i].Default,
+			Labels:     labels,
+			Range:      rangeType,
+			NotNull:    dbTypes[i].NotNull,
+		})
+	}
+
+	return details, nil
+}
+
 func (r Processor) ProcessTypes(types []driver.Type) []driver.Type {
 	processType := map[string]string{
 		"b": "base",
@@ -126,3 +605,58 @@ func (r Processor) ProcessTypes(types []driver.Type) []driver.Type {
 
 	return types
 }
+
+// columnType converts a PostgreSQL type to the column type of the grammar, falling back to the formatted
+// type which the grammar uses as is, e.g. int4 to integer and _varchar, character varying(255)[] to string(255)[].
+func columnType(typeName, formattedType string) string {
+	elementTypeName := strings.TrimPrefix(typeName, "_")
+	elementType, dimensions := parseArrayType(formattedType)
+	if elementTypeName != typeName && dimensions == 0 {
+		return formattedType
+	}
+
+	ttype, ok := columnTypes[elementTypeName]
+	if !ok || (ttype == "interval" && strings.Contains(elementType, " ")) {
+		return formattedType
+	}
+
+	if matches := formatTypeModifierRegex.FindStringSubmatch(elementType); len(matches) == 2 {
+		ttype += "(" + matches[1] + ")"
+	} else if strings.Contains(elementType, "(") || slices.Contains(columnTypesWithModifiers, ttype) {
+		return formattedType
+	}
+
+	return ttype + strings.Repeat(arraySuffix, dimensions)
+}
+
+// checkExpression extracts the expression of a check constraint definition, e.g. CHECK ((VALUE > 0)) to (VALUE > 0).
+func checkExpression(definition string) string {
+	definition = strings.TrimSuffix(definition, " NOT VALID")
+	if strings.HasPrefix(definition, "CHECK (") && strings.HasSuffix(definition, ")") {
+		return strings.TrimSuffix(strings.TrimPrefix(definition, "CHECK ("), ")")
+	}
+
+	return definition
+}
+
+// typeModifiers parses the explicit length, precision and scale of a formatted type, the other modifiers,
+// e.g. the dimensions of vector(3), are none of them.
+func typeModifiers(formattedType string) (length, precision, scale int) {
+	elementType, _ := parseArrayType(formattedType)
+	matches := formatTypeModifierRegex.FindStringSubmatch(elementType)
+	if len(matches) != 2 {
+		return
+	}
+
+	first, second, _ := strings.Cut(matches[1], ",")
+	switch {
+	case strings.HasPrefix(elementType, "character"), strings.HasPrefix(elementType, "bit"):
+		length = cast.ToInt(first)
+	case strings.HasPrefix(elementType, "numeric"):
+		precision, scale = cast.ToInt(first), cast.ToInt(second)
+	case strings.HasPrefix(elementType, "time"), strings.HasPrefix(elementType, "interval"):
+		precision = cast.ToInt(first)
+	}
+
+	return
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/goravel/framework/contracts/database/driver"
+
+	"github.com/goravel/postgres/contracts"
 )
 
 type ProcessorTestSuite struct {
@@ -21,6 +23,16 @@ func (s *ProcessorTestSuite) SetupTest() {
 	s.processor = NewProcessor()
 }
 
+func (s *ProcessorTestSuite) TestProcessChecks() {
+	s.Equal([]contracts.Check{
+		{Name: "users_age_check", Expression: "(age > 0)"},
+		{Name: "users_email_check", Expression: "((email)::text ~~ '%@%'::text)", NotValid: true},
+	}, s.processor.ProcessChecks([]DBCheck{
+		{Name: "users_age_check", Definition: "CHECK ((age > 0))"},
+		{Name: "users_email_check", Definition: "CHECK (((email)::text ~~ '%@%'::text)) NOT VALID"},
+	}))
+}
+
 func (s *ProcessorTestSuite) TestProcessColumns() {
 	tests := []struct {
 		name      string
@@ -42,6 +54,15 @@ func (s *ProcessorTestSuite) TestProcessColumns() {
 			name:      "EmptyInput",
 			dbColumns: []driver.DBColumn{},
 		},
+		{
+			name: "IdentityColumn",
+			dbColumns: []driver.DBColumn{
+				{Name: "id", Type: "bigint", TypeName: "int8", Nullable: "false", Autoincrement: true},
+			},
+			expected: []driver.Column{
+				{Autoincrement: true, Name: "id", Nullable: false, Type: "bigint", TypeName: "int8"},
+			},
+		},
 		{
 			name: "NullableColumn",
 			dbColumns: []driver.DBColumn{
@@ -71,6 +92,167 @@ func (s *ProcessorTestSuite) TestProcessColumns() {
 	}
 }
 
+func (s *ProcessorTestSuite) TestProcessColumnDetails() {
+	dbColumns := []DBColumn{
+		{DBColumn: driver.DBColumn{Name: "id", Type: "bigint", TypeName: "int8", Nullable: "false", Default: "nextval('id_seq'::regclass)"}},
+		{DBColumn: driver.DBColumn{Name: "tags", Type: "character varying(255)[]", TypeName: "_varchar", Nullable: "true", Default: "'{}'::character varying[]"}, Dimensions: 2},
+		{DBColumn: driver.DBColumn{Name: "scores", Type: "_int4", TypeName: "_int4", Nullable: "false"}},
+		{DBColumn: driver.DBColumn{Name: "number", Type: "integer", TypeName: "int4", Nullable: "false", Autoincrement: true}, Identity: "by default"},
+		{DBColumn: driver.DBColumn{Name: "total", Type: "numeric(10,2)", TypeName: "numeric", Nullable: "true"}, Generated: "stored", Generation: "(price * (quantity)::numeric)"},
+		{DBColumn: driver.DBColumn{Name: "email", Type: "email", TypeName: "email", Nullable: "true"}, BaseType: "character varying(255)"},
+		{DBColumn: driver.DBColumn{Name: "created_at", Type: "timestamp(3) with time zone", TypeName: "timestamptz", Nullable: "true"}},
+		{DBColumn: driver.DBColumn{Name: "bio", Type: "text", TypeName: "text", Nullable: "true", Collation: "C"}, Compression: "lz4", Storage: "external"},
+	}
+
+	s.Equal([]Column{
+		{
+			Column:     driver.Column{Autoincrement: true, Default: "nextval('id_seq'::regclass)", Name: "id", Nullable: false, Type: "bigint", TypeName: "int8"},
+			ColumnType: "bigInteger",
+		},
+		{
+			Column:      driver.Column{Default: "'{}'::character varying[]", Name: "tags", Nullable: true, Type: "character varying(255)[]", TypeName: "_varchar"},
+			Array:       true,
+			ColumnType:  "string(255)[]",
+			Dimensions:  2,
+			ElementType: "character varying(255)",
+			Length:      255,
+		},
+		{
+			Column:      driver.Column{Name: "scores", Nullable: false, Type: "_int4", TypeName: "_int4"},
+			Array:       true,
+			ColumnType:  "_int4",
+			Dimensions:  1,
+			ElementType: "int4",
+		},
+		{
+			Column:     driver.Column{Autoincrement: true, Name: "number", Nullable: false, Type: "integer", TypeName: "int4"},
+			ColumnType: "integer",
+			Identity:   "by default",
+		},
+		{
+			Column:     driver.Column{Name: "total", Nullable: true, Type: "numeric(10,2)", TypeName: "numeric"},
+			ColumnType: "decimal(10,2)",
+			Generated:  "stored",
+			Generation: "(price * (quantity)::numeric)",
+			Precision:  10,
+			Scale:      2,
+		},
+		{
+			Column:     driver.Column{Name: "email", Nullable: true, Type: "email", TypeName: "email"},
+			BaseType:   "character varying(255)",
+			ColumnType: "email",
+			Length:     255,
+		},
+		{
+			Column:     driver.Column{Name: "created_at", Nullable: true, Type: "timestamp(3) with time zone", TypeName: "timestamptz"},
+			ColumnType: "timestampTz(3)",
+			Precision:  3,
+		},
+		{
+			Column:      driver.Column{Collation: "C", Name: "bio", Nullable: true, Type: "text", TypeName: "text"},
+			ColumnType:  "text",
+			Compression: "lz4",
+			Storage:     "external",
+		},
+	}, s.processor.ProcessColumnDetails(dbColumns))
+}
+
+func (s *ProcessorTestSuite) TestTypeModifiers() {
+	tests := []struct {
+		formattedType string
+		length        int
+		precision     int
+		scale         int
+	}{
+		{"integer", 0, 0, 0},
+		{"character varying", 0, 0, 0},
+		{"character varying(100)", 100, 0, 0},
+		{"character(2)[]", 2, 0, 0},
+		{"bit varying(64)", 64, 0, 0},
+		{"numeric(10)", 0, 10, 0},
+		{"numeric(10,2)", 0, 10, 2},
+		{"time(6) without time zone", 0, 6, 0},
+		{"interval day to second(3)", 0, 3, 0},
+		{"vector(1536)", 0, 0, 0},
+		{"geometry(Point,4326)", 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		s.Run(test.formattedType, func() {
+			length, precision, scale := typeModifiers(test.formattedType)
+			s.Equal(test.length, length)
+			s.Equal(test.precision, precision)
+			s.Equal(test.scale, scale)
+		})
+	}
+}
+
+func (s *ProcessorTestSuite) TestColumnType() {
+	tests := []struct {
+		typeName      string
+		formattedType string
+		expected      string
+	}{
+		{"int4", "integer", "integer"},
+		{"varchar", "character varying", "string"},
+		{"varchar", "character varying(100)", "string(100)"},
+		{"numeric", "numeric(10,2)", "decimal(10,2)"},
+		{"numeric", "numeric", "numeric"},
+		{"timestamptz", "timestamp(3) with time zone", "timestampTz(3)"},
+		{"timestamp", "timestamp without time zone", "timestamp without time zone"},
+		{"inet", "inet", "inet"},
+		{"cidr", "cidr", "cidr"},
+		{"macaddr8", "macaddr8", "macAddress8"},
+		{"bit", "bit(8)", "bit(8)"},
+		{"varbit", "bit varying(64)", "varBit(64)"},
+		{"money", "money", "money"},
+		{"interval", "interval", "interval"},
+		{"interval", "interval(3)", "interval(3)"},
+		{"interval", "interval day to second(3)", "interval day to second(3)"},
+		{"xml", "xml", "xml"},
+		{"bytea", "bytea", "binary"},
+		{"tsvector", "tsvector", "tsVector"},
+		{"tsquery", "tsquery", "tsQuery"},
+		{"_inet", "inet[]", "inet[]"},
+		{"tstzrange", "tstzrange", "tsTzRange"},
+		{"int8multirange", "int8multirange", "int8MultiRange"},
+		{"citext", "citext", "citext"},
+		{"geometry", "geometry", "geometry"},
+		{"geometry", "geometry(Point,4326)", "geometry(Point,4326)"},
+		{"geography", "geography(Point,4326)", "geography(Point,4326)"},
+		{"vector", "vector(1536)", "vector(1536)"},
+		{"halfvec", "halfvec(3072)", "halfVector(3072)"},
+		{"sparsevec", "sparsevec", "sparseVector"},
+	}
+
+	for _, test := range tests {
+		s.Run(test.formattedType, func() {
+			s.Equal(test.expected, columnType(test.typeName, test.formattedType))
+		})
+	}
+}
+
+func (s *ProcessorTestSuite) TestProcessForeignKeyDetails() {
+	s.Equal([]ForeignKey{
+		{
+			ForeignKey: driver.ForeignKey{Name: "fk_user_id", Columns: []string{"user_id"}, ForeignSchema: "public", ForeignTable: "users", ForeignColumns: []string{"id"}, OnUpdate: "no action", OnDelete: "cascade"},
+			Match:      "full", Deferrable: true, InitiallyDeferred: true,
+		},
+		{
+			ForeignKey: driver.ForeignKey{Name: "fk_team_id", Columns: []string{"team_id"}, ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: []string{"id"}, OnUpdate: "no action", OnDelete: "no action"},
+		},
+	}, s.processor.ProcessForeignKeyDetails([]DBForeignKey{
+		{
+			DBForeignKey: driver.DBForeignKey{Name: "fk_user_id", Columns: "user_id", ForeignSchema: "public", ForeignTable: "users", ForeignColumns: "id", OnUpdate: "a", OnDelete: "c"},
+			Match:        "f", Deferrable: true, InitiallyDeferred: true,
+		},
+		{
+			DBForeignKey: driver.DBForeignKey{Name: "fk_team_id", Columns: "team_id", ForeignSchema: "public", ForeignTable: "teams", ForeignColumns: "id", OnUpdate: "a", OnDelete: "a"},
+			Match:        "s",
+		},
+	}))
+}
+
 func (s *ProcessorTestSuite) TestProcessForeignKeys() {
 	tests := []struct {
 		name          string
@@ -100,6 +282,32 @@ func (s *ProcessorTestSuite) TestProcessForeignKeys() {
 	}
 }
 
+func (s *ProcessorTestSuite) TestProcessFunctions() {
+	s.Equal([]Function{
+		{Name: "add", Volatility: "immutable"},
+		{Name: "now_utc", Volatility: "stable"},
+		{Name: "touch", Volatility: "volatile"},
+	}, s.processor.ProcessFunctions([]Function{
+		{Name: "add", Volatility: "i"},
+		{Name: "now_utc", Volatility: "s"},
+		{Name: "touch", Volatility: "v"},
+	}))
+}
+
+func (s *ProcessorTestSuite) TestProcessIndexDetails() {
+	s.Equal([]Index{
+		{
+			Index:      driver.Index{Name: "users_name_trgm_index", Columns: []string{"name"}, Type: "gin"},
+			Definition: "CREATE INDEX users_name_trgm_index ON public.users USING gin (name gin_trgm_ops)",
+		},
+	}, s.processor.ProcessIndexDetails([]DBIndex{
+		{
+			DBIndex:    driver.DBIndex{Name: "users_name_trgm_index", Columns: "name", Type: "GIN"},
+			Definition: "CREATE INDEX users_name_trgm_index ON public.users USING gin (name gin_trgm_ops)",
+		},
+	}))
+}
+
 func (s *ProcessorTestSuite) TestProcessIndexes() {
 	tests := []struct {
 		name      string
@@ -133,6 +341,80 @@ func (s *ProcessorTestSuite) TestProcessIndexes() {
 	}
 }
 
+func (s *ProcessorTestSuite) TestProcessPolicies() {
+	s.Equal([]Policy{
+		{Name: "tenant_isolation", As: "permissive", Command: "all", Using: "(tenant_id = 1)", Roles: []string{"app", "public"}},
+	}, s.processor.ProcessPolicies([]DBPolicy{
+		{Name: "tenant_isolation", As: "PERMISSIVE", Command: "ALL", Roles: "app,public", Using: "(tenant_id = 1)"},
+	}))
+}
+
+func (s *ProcessorTestSuite) TestProcessTriggers() {
+	s.Equal([]Trigger{
+		{
+			Name:      "users_audit",
+			Timing:    "after",
+			ForEach:   "row",
+			When:      "(pg_trigger_depth() = 0)",
+			Statement: "EXECUTE FUNCTION audit()",
+			Events:    []string{"insert", `update of "name", "email"`, "truncate"},
+			Definition: `CREATE TRIGGER users_audit AFTER INSERT OR UPDATE OF name, email OR TRUNCATE ON public.goravel_users ` +
+				`FOR EACH ROW WHEN ((pg_trigger_depth() = 0)) EXECUTE FUNCTION audit()`,
+		},
+	}, s.processor.ProcessTriggers([]DBTrigger{
+		{
+			Name: "users_audit", Events: `insert or update of "name", "email" or truncate`, Timing: "after", ForEach: "row", When: "(pg_trigger_depth() = 0)", Statement: "EXECUTE FUNCTION audit()",
+			Definition: `CREATE TRIGGER users_audit AFTER INSERT OR UPDATE OF name, email OR TRUNCATE ON public.goravel_users ` +
+				`FOR EACH ROW WHEN ((pg_trigger_depth() = 0)) EXECUTE FUNCTION audit()`,
+		},
+	}))
+}
+
+func (s *ProcessorTestSuite) TestProcessTypeDetails() {
+	dbTypes := []DBType{
+		{Type: driver.Type{Name: "status", Schema: "public", Type: "e", Category: "e"}, EnumLabels: `["active", "banned"]`},
+		{
+			Type:     driver.Type{Name: "email", Schema: "public", Type: "d", Category: "s"},
+			BaseType: "character varying(255)",
+			Constraints: `[{"name": "email_check", "definition": "CHECK (((VALUE)::text ~ '^.+@.+$'::text))"}, ` +
+				`{"name": "email_length", "definition": "CHECK ((length((VALUE)::text) <\n255)) NOT VALID"}]`,
+			Default: "'nobody'::character varying",
+			NotNull: true,
+		},
+		{Type: driver.Type{Name: "address", Schema: "public", Type: "c", Category: "c"}, Attributes: `[{"name": "street", "type": "text", "collation": "C"}]`},
+		{Type: driver.Type{Name: "floatrange", Schema: "public", Type: "r", Category: "r"}, Range: `{"subtype": "double precision", "subtypeDiff": "float8mi"}`},
+	}
+
+	types, err := s.processor.ProcessTypeDetails(dbTypes)
+	s.NoError(err)
+	s.Equal([]Type{
+		{Type: driver.Type{Name: "status", Schema: "public", Type: "enum", Category: "enum"}, Labels: []string{"active", "banned"}},
+		{
+			Type:     driver.Type{Name: "email", Schema: "public", Type: "domain", Category: "string"},
+			BaseType: "character varying(255)",
+			Checks: []contracts.Check{
+				{Name: "email_check", Expression: "((VALUE)::text ~ '^.+@.+$'::text)"},
+				{Name: "email_length", Expression: "(length((VALUE)::text) <\n255)", NotValid: true},
+			},
+			Default: "'nobody'::character varying",
+			NotNull: true,
+		},
+		{
+			Type:       driver.Type{Name: "address", Schema: "public", Type: "composite", Category: "composite"},
+			Attributes: []TypeAttribute{{Name: "street", Type: "text", Collation: "C"}},
+		},
+		{
+			Type:  driver.Type{Name: "floatrange", Schema: "public", Type: "range", Category: "range"},
+			Range: contracts.RangeType{Subtype: "double precision", SubtypeDiff: "float8mi"},
+		},
+	}, types)
+
+	_, err = s.processor.ProcessTypeDetails([]DBType{
+		{Type: driver.Type{Name: "status", Schema: "public", Type: "e", Category: "e"}, EnumLabels: `active`},
+	})
+	s.Error(err)
+}
+
 func (s *ProcessorTestSuite) TestProcessTypes() {
 	// ValidTypes_ReturnsProcessedTypes
 	input := []driver.Type{